@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// branchRepo is what the branch command needs from git.
+type branchRepo interface {
+	LatestLineTag(ctx context.Context, line version.Line) (string, error)
+	TagExists(ctx context.Context, tag string) (bool, error)
+	CreateBranch(ctx context.Context, branch, rev string) error
+}
+
+// runBranch creates the maintenance branch of a line, such as release/1.4,
+// for patch releases of it. Given a line it starts from the line's highest
+// release tag; given a release, from that release's tag.
+func runBranch(ctx context.Context, repo branchRepo, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("branch", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: branch takes a line such as 1.4 or a release such as v1.4.2", errUnknownCommand)
+	}
+
+	var (
+		line version.Line
+		tag  string
+	)
+	if ver, err := parseRelease(tags, fs.Arg(0)); err == nil {
+		line = version.Line{Major: ver.Major, Minor: ver.Minor}
+		tag = tags.Name(ver)
+		ok, err := repo.TagExists(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", errNoReleaseTag, tag)
+		}
+	} else {
+		if line, err = version.ParseLine(strings.TrimPrefix(fs.Arg(0), "v")); err != nil {
+			return err
+		}
+		if tag, err = repo.LatestLineTag(ctx, line); err != nil {
+			return err
+		}
+		if tag == "" {
+			return fmt.Errorf("%w: for %s", errNoReleaseTag, line)
+		}
+	}
+
+	branch := git.ReleaseBranch(line)
+	if err := repo.CreateBranch(ctx, branch, tag); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "created %s from %s; check it out to release patches of %s\n", branch, tag, line)
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestRunBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput string
+		wantBranch string
+		wantRev    string
+		wantErr    error
+	}{
+		{
+			name:       "highest tag of the line",
+			args:       []string{"1.4"},
+			wantOutput: "created release/1.4 from v1.4.1; check it out to release patches of 1.4\n",
+			wantBranch: "release/1.4",
+			wantRev:    "v1.4.1",
+		},
+		{
+			name:       "given release",
+			args:       []string{"v1.4.0"},
+			wantOutput: "created release/1.4 from v1.4.0; check it out to release patches of 1.4\n",
+			wantBranch: "release/1.4",
+			wantRev:    "v1.4.0",
+		},
+		{
+			name:    "line without releases",
+			args:    []string{"1.3"},
+			wantErr: errNoReleaseTag,
+		},
+		{
+			name:    "missing release",
+			args:    []string{"1.4.7"},
+			wantErr: errNoReleaseTag,
+		},
+		{
+			name:    "not a line",
+			args:    []string{"next"},
+			wantErr: version.ErrInvalidLine,
+		},
+		{
+			name:    "line is required",
+			wantErr: errUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			runGit(t, dir, "init", "-b", "main")
+			runGit(t, dir, "config", "user.name", "Test User")
+			runGit(t, dir, "config", "user.email", "test@example.com")
+			for _, tag := range []string{"v1.4.0", "v1.4.1", "v1.5.0"} {
+				runGit(t, dir, "commit", "--allow-empty", "-m", "chore(release): "+tag)
+				runGit(t, dir, "tag", tag)
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+
+			var out bytes.Buffer
+			err := runBranch(context.Background(), git.New(), nil, tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOutput)
+			}
+			if tt.wantBranch == "" {
+				return
+			}
+			if got, want := runGit(t, dir, "rev-parse", tt.wantBranch), runGit(t, dir, "rev-parse", tt.wantRev+"^{commit}"); got != want {
+				t.Errorf("%s = %s, want %s (%s)", tt.wantBranch, got, want, tt.wantRev)
+			}
+			if head := runGit(t, dir, "branch", "--show-current"); head != "main" {
+				t.Errorf("checked out %s, want main", head)
+			}
+		})
+	}
+}
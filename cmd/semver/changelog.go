@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+)
+
+var (
+	errLintFailed      = errors.New("changelog has lint errors")
+	errChangelogExists = errors.New("changelog already exists")
+)
+
+func runChangelog(ctx context.Context, cfg *config.Config, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: changelog requires a subcommand", errUnknownCommand)
+	}
+
+	switch args[0] {
+	case "export":
+		return runChangelogExport(cfg, args[1:], stdout)
+	case "lint":
+		return runChangelogLint(cfg, args[1:], stdout)
+	case "import":
+		return runChangelogImport(cfg, args[1:], stdout)
+	default:
+		return fmt.Errorf("%w: changelog %s", errUnknownCommand, args[0])
+	}
+}
+
+func runChangelogExport(cfg *config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("changelog export", flag.ContinueOnError)
+	format := fs.String("format", string(changelog.FormatHTML), "output format: html, json, atom or rss")
+	output := fs.String("o", "", "write to `file` instead of stdout")
+	title := fs.String("title", "", "document and feed title")
+	url := fs.String("url", "", "public `URL` of the changelog, used for feed links")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cl, err := changelog.ParseFile(cfg.ChangelogFile)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	opts := changelog.ExportOptions{Title: *title, URL: *url, DateLayout: cfg.Changelog.DateLayout}
+	if info, err := os.Stat(cfg.ChangelogFile); err == nil {
+		opts.Modified = info.ModTime()
+	}
+	if err := changelog.Export(w, cl, changelog.Format(*format), opts); err != nil {
+		return fmt.Errorf("exporting changelog: %w", err)
+	}
+
+	return nil
+}
+
+func runChangelogLint(cfg *config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("changelog lint", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "apply safe automatic fixes before reporting")
+	requireUnreleased := fs.Bool("require-unreleased", false, "report a missing Unreleased section")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := changelog.LintOptions{RequireUnreleased: *requireUnreleased, DateLayout: cfg.Changelog.DateLayout}
+
+	src, err := os.ReadFile(cfg.ChangelogFile)
+	if err != nil {
+		return fmt.Errorf("reading changelog: %w", err)
+	}
+
+	if *fix {
+		fixed, err := changelog.Fix(src, opts)
+		if err != nil {
+			return fmt.Errorf("fixing changelog: %w", err)
+		}
+		if !bytes.Equal(fixed, src) {
+			if err := os.WriteFile(cfg.ChangelogFile, fixed, 0644); err != nil {
+				return fmt.Errorf("writing changelog: %w", err)
+			}
+			src = fixed
+		}
+	}
+
+	cl, err := changelog.Parse(bytes.NewReader(src))
+	if err != nil {
+		return err
+	}
+
+	name := cfg.ChangelogFile
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, name); err == nil {
+			name = rel
+		}
+	}
+
+	issues := changelog.Lint(cl, opts)
+	for _, issue := range issues {
+		msg := issue.Message
+		if issue.Fixable {
+			msg += " (fixable with --fix)"
+		}
+		fmt.Fprintf(stdout, "%s:%d: %s\n", name, issue.Line, msg)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %d problem(s)", errLintFailed, len(issues))
+	}
+	return nil
+}
+
+func runChangelogImport(cfg *config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("changelog import", flag.ContinueOnError)
+	from := fs.String("from", string(changelog.ImportConventional), "input format: conventional, github or news")
+	output := fs.String("o", "", "write to `file` instead of the configured changelog")
+	force := fs.Bool("force", false, "overwrite an existing changelog")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: changelog import requires one input file", errUnknownCommand)
+	}
+	input := fs.Arg(0)
+
+	dest := *output
+	if dest == "" {
+		dest = cfg.ChangelogFile
+	}
+	if !*force {
+		if info, err := os.Stat(dest); err == nil && info.Size() > 0 {
+			return fmt.Errorf("%w: %s (use --force to overwrite)", errChangelogExists, dest)
+		}
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer f.Close()
+
+	cl, issues, err := changelog.Import(f, changelog.ImportFormat(*from))
+	if err != nil {
+		return fmt.Errorf("importing changelog: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := changelog.Render(&buf, cl); err != nil {
+		return err
+	}
+	if err := os.WriteFile(dest, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(stdout, "%s:%d: %s\n", input, issue.Line, issue.Message)
+	}
+	fmt.Fprintf(stdout, "imported %d release(s) into %s\n", len(cl.Releases), dest)
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunChangelogExport(t *testing.T) {
+	dir := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	content := "## [1.0.0] - 2024-12-23\n### Major\n- Initial commit\n"
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create changelog: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "json to stdout",
+			args: []string{"changelog", "export", "--format", "json"},
+			want: `"version": "1.0.0"`,
+		},
+		{
+			name: "atom to stdout",
+			args: []string{"changelog", "export", "--format=atom", "--title", "Releases"},
+			want: "<title>Releases</title>",
+		},
+		{
+			name:    "unknown subcommand",
+			args:    []string{"changelog", "publish"},
+			wantErr: errUnknownCommand,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"frobnicate"},
+			wantErr: errUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runCommand(context.Background(), slog.Default(), tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+
+	t.Run("html to file", func(t *testing.T) {
+		output := filepath.Join(dir, "changelog.html")
+		args := []string{"changelog", "export", "-o", output}
+		if err := runCommand(context.Background(), slog.Default(), args, &bytes.Buffer{}); err != nil {
+			t.Fatalf("runCommand() error = %v", err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		if !strings.Contains(string(data), `id="v1.0.0"`) {
+			t.Errorf("html output missing release anchor")
+		}
+	})
+}
+
+func TestRunChangelogLint(t *testing.T) {
+	dir := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "clean changelog",
+			content: "## [1.0.0] - 2024-12-23\n### Added\n- Initial commit\n",
+			args:    []string{"changelog", "lint"},
+		},
+		{
+			name:    "problems are reported with file and line",
+			content: "## [1.0.0] - 2024-12-23\n### Added\n\n## [1.0.0] - 2024-12-24\n### Added\n- Again\n",
+			args:    []string{"changelog", "lint"},
+			want: []string{
+				`CHANGELOG.md:2: empty section "Added" (fixable with --fix)`,
+				"CHANGELOG.md:4: duplicate version 1.0.0 (first seen on line 1)",
+			},
+			wantErr: errLintFailed,
+		},
+		{
+			name:    "fix resolves safe problems",
+			content: "## [1.0.0] - 2024-12-23\n### added\n- Initial commit\n",
+			args:    []string{"changelog", "lint", "--fix", "--require-unreleased"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(changelogFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create changelog: %v", err)
+			}
+
+			var out bytes.Buffer
+			err := runCommand(context.Background(), slog.Default(), tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runCommand() error = %v, wantErr %v\n%s", err, tt.wantErr, out.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunChangelogImport(t *testing.T) {
+	dir := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	news := "1.0.0 (2024-12-23)\n\n* Fixed startup\n\nThanks!\n"
+	if err := os.WriteFile(filepath.Join(dir, "NEWS"), []byte(news), 0644); err != nil {
+		t.Fatalf("Failed to create input: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "imports into the configured changelog",
+			args: []string{"changelog", "import", "--from", "news", "NEWS"},
+			want: `NEWS:5: unmapped text: "Thanks!"`,
+		},
+		{
+			name:    "refuses to overwrite",
+			args:    []string{"changelog", "import", "--from", "news", "NEWS"},
+			wantErr: errChangelogExists,
+		},
+		{
+			name: "overwrites with force",
+			args: []string{"changelog", "import", "--from=news", "--force", "NEWS"},
+			want: "imported 1 release(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runCommand(context.Background(), slog.Default(), tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("Failed to read changelog: %v", err)
+	}
+	want := "# Changelog\n\n## [1.0.0] - 2024-12-23\n### Fixed\n- Fixed startup\n"
+	if string(data) != want {
+		t.Errorf("changelog =\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/hooks"
+)
+
+// hooksRepo is what the hooks commands need from git.
+type hooksRepo interface {
+	HooksDir(ctx context.Context) (string, error)
+	hooks.Repo
+}
+
+// runHooks installs and removes the git hooks that enforce release
+// conventions, and runs their checks when git calls them.
+func runHooks(ctx context.Context, cfg *config.Config, repo hooksRepo, tags *git.TagFormat, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: hooks requires a subcommand: install, uninstall or run", errUnknownCommand)
+	}
+
+	switch args[0] {
+	case "install", "uninstall":
+		if len(args) > 1 {
+			return fmt.Errorf("%w: unexpected argument %q", errUnknownCommand, args[1])
+		}
+		dir, err := repo.HooksDir(ctx)
+		if err != nil {
+			return err
+		}
+		if args[0] == "install" {
+			return installHooks(dir, stdout)
+		}
+		return uninstallHooks(dir, stdout)
+	case "run":
+		return runHook(ctx, cfg, repo, tags, args[1:], stdin)
+	default:
+		return fmt.Errorf("%w: hooks %s", errUnknownCommand, args[0])
+	}
+}
+
+func installHooks(dir string, stdout io.Writer) error {
+	changes, err := hooks.Install(dir)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		fmt.Fprintf(stdout, "installed the %s hook in %s\n", c.Hook, dir)
+		if c.Chained {
+			fmt.Fprintf(stdout, "  your previous hook is kept as %s.local and runs first\n", c.Hook)
+		}
+	}
+	return nil
+}
+
+func uninstallHooks(dir string, stdout io.Writer) error {
+	changes, err := hooks.Uninstall(dir)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(stdout, "no semver hooks installed in %s\n", dir)
+	}
+	for _, c := range changes {
+		fmt.Fprintf(stdout, "removed the %s hook from %s\n", c.Hook, dir)
+		if c.Chained {
+			fmt.Fprintf(stdout, "  restored your previous %s hook\n", c.Hook)
+		}
+	}
+	return nil
+}
+
+// runHook runs the check of a hook with the arguments and input git gave
+// it.
+func runHook(ctx context.Context, cfg *config.Config, repo hooksRepo, tags *git.TagFormat, args []string, stdin io.Reader) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: hooks run requires a hook name", errUnknownCommand)
+	}
+
+	switch args[0] {
+	case "commit-msg":
+		if len(args) != 2 {
+			return fmt.Errorf("%w: the commit-msg hook takes the message file", errUnknownCommand)
+		}
+		message, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		return hooks.CheckCommitMsg(string(message))
+	case "pre-push":
+		// The remote name and URL aren't needed.
+		return hooks.CheckPush(ctx, repo, hooks.PushOptions{
+			VersionFile:   cfg.VersionFile,
+			ChangelogFile: cfg.ChangelogFile,
+			Tags:          tags,
+		}, stdin)
+	default:
+		return fmt.Errorf("%w: hooks run %s", errUnknownCommand, args[0])
+	}
+}
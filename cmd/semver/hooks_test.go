@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/conventional"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/hooks"
+)
+
+func TestRunHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "core.hooksPath", "githooks")
+	files := map[string]string{
+		"VERSION.md":   "1.1.0\n",
+		"CHANGELOG.md": "## [1.1.0] - 2024-12-24\n### Added\n- Export\n",
+		"MSG_OK":       "feat: add export\n",
+		"MSG_BAD":      "Added export\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "add", "VERSION.md", "CHANGELOG.md")
+	runGit(t, dir, "commit", "-m", "chore(release): v1.1.0")
+	runGit(t, dir, "tag", "v1.1.0")
+	runGit(t, dir, "tag", "v1.2.0")
+	head := runGit(t, dir, "rev-parse", "HEAD")
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	cfg := &config.Config{
+		VersionFile:   filepath.Join(dir, "VERSION.md"),
+		ChangelogFile: filepath.Join(dir, "CHANGELOG.md"),
+	}
+	const zero = "0000000000000000000000000000000000000000"
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantOutput string
+		wantErr    error
+	}{
+		{
+			name: "install into core.hooksPath",
+			args: []string{"install"},
+			wantOutput: "installed the commit-msg hook in " + filepath.Join(dir, "githooks") + "\n" +
+				"installed the pre-push hook in " + filepath.Join(dir, "githooks") + "\n",
+		},
+		{
+			name: "conventional commit message",
+			args: []string{"run", "commit-msg", "MSG_OK"},
+		},
+		{
+			name:    "other commit message",
+			args:    []string{"run", "commit-msg", "MSG_BAD"},
+			wantErr: conventional.ErrNotConventional,
+		},
+		{
+			name:  "pushing a matching tag",
+			args:  []string{"run", "pre-push", "origin", "https://example.com/repo.git"},
+			stdin: "refs/heads/main " + head + " refs/heads/main " + zero + "\nrefs/tags/v1.1.0 " + head + " refs/tags/v1.1.0 " + zero + "\n",
+		},
+		{
+			name:    "pushing a tag VERSION.md doesn't match",
+			args:    []string{"run", "pre-push", "origin", "https://example.com/repo.git"},
+			stdin:   "refs/tags/v1.2.0 " + head + " refs/tags/v1.2.0 " + zero + "\n",
+			wantErr: hooks.ErrPushRefused,
+		},
+		{
+			name:    "unknown hook",
+			args:    []string{"run", "post-commit"},
+			wantErr: errUnknownCommand,
+		},
+		{
+			name:       "uninstall",
+			args:       []string{"uninstall"},
+			wantOutput: "removed the commit-msg hook from " + filepath.Join(dir, "githooks") + "\nremoved the pre-push hook from " + filepath.Join(dir, "githooks") + "\n",
+		},
+		{
+			name:       "nothing to uninstall",
+			args:       []string{"uninstall"},
+			wantOutput: "no semver hooks installed in " + filepath.Join(dir, "githooks") + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runHooks(context.Background(), cfg, git.New(), nil, tt.args, strings.NewReader(tt.stdin), &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runHooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
@@ -2,24 +2,48 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"github.com/WagnerMatos/semver/internal/config"
-	"github.com/WagnerMatos/semver/internal/tui"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/tui"
 )
 
-func run(ctx context.Context, logger *slog.Logger, testing bool) error {
+var errUnknownCommand = errors.New("unknown command")
+
+func run(ctx context.Context, logger *slog.Logger, testing bool, args ...string) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	fs := flag.NewFlagSet("semver", flag.ContinueOnError)
+	fs.BoolVar(&cfg.Changelog.FromCommits, "from-commits", cfg.Changelog.FromCommits,
+		"pre-fill changelog entries from the commits since the last release tag")
+	fs.BoolVar(&cfg.IncludeStaged, "include-staged", cfg.IncludeStaged,
+		"commit changes staged before the release along with it")
+	fs.BoolVar(&cfg.ReleaseCommit.SignOff, "s", cfg.ReleaseCommit.SignOff,
+		"add a Signed-off-by trailer to the release commit")
+	skipChecks(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
 	var app *tui.App
 	if testing {
-		app = tui.NewTest(cfg, logger)
+		app, err = tui.NewTest(cfg, logger)
 	} else {
-		app = tui.New(cfg, logger)
+		app, err = tui.New(cfg, logger)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start: %w", err)
 	}
 
 	if err := app.Run(ctx); err != nil {
@@ -29,11 +53,90 @@ func run(ctx context.Context, logger *slog.Logger, testing bool) error {
 	return nil
 }
 
+// skipChecks adds the --skip-check flag, which may be repeated or given a
+// comma-separated list.
+func skipChecks(fs *flag.FlagSet, cfg *config.Config) {
+	fs.Func("skip-check", "skip a preflight `check`: clean, branch, upstream, tag or identity", func(s string) error {
+		for _, c := range strings.Split(s, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				cfg.Preflight.Skip = append(cfg.Preflight.Skip, c)
+			}
+		}
+		return nil
+	})
+}
+
+// runCommand dispatches the non-interactive subcommands. Running semver
+// without a command starts the TUI instead.
+func runCommand(ctx context.Context, logger *slog.Logger, args []string, stdout io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	backend, err := git.NewBackend(cfg.GitBackend)
+	if err != nil {
+		return err
+	}
+	tags, err := git.NewTagFormat(cfg.Tag.Format, cfg.Tag.Package)
+	if err != nil {
+		return err
+	}
+	yanked, err := changelog.YankedFile(cfg.ChangelogFile)
+	if err != nil {
+		return err
+	}
+	gitOpts := []git.Option{
+		git.WithBackend(backend),
+		git.WithTagFormat(tags),
+		git.WithYanked(yanked...),
+		git.WithSignOff(cfg.ReleaseCommit.SignOff),
+	}
+	if cfg.Tag.Sign {
+		gitOpts = append(gitOpts, git.WithSigning(git.Signing{
+			Key:    cfg.Tag.SigningKey,
+			Format: cfg.Tag.SigningFormat,
+		}))
+	}
+
+	switch args[0] {
+	case "branch":
+		return runBranch(ctx, git.New(gitOpts...), tags, args[1:], stdout)
+	case "changelog":
+		return runChangelog(ctx, cfg, args[1:], stdout)
+	case "hooks":
+		return runHooks(ctx, cfg, git.New(gitOpts...), tags, args[1:], os.Stdin, stdout)
+	case "next":
+		return runNext(ctx, cfg, git.New(gitOpts...), args[1:], stdout)
+	case "notes":
+		return runNotes(ctx, logger, git.New(gitOpts...), tags, args[1:], stdout)
+	case "release":
+		return runRelease(ctx, logger, cfg, tags, args[1:], stdout)
+	case "stats":
+		return runStats(ctx, git.New(gitOpts...), tags, args[1:], stdout)
+	case "tag":
+		return runTag(ctx, git.New(gitOpts...), tags, args[1:], stdout)
+	case "undo":
+		return runUndo(ctx, cfg, git.New(gitOpts...), tags, args[1:], stdout)
+	case "yank":
+		return runYank(ctx, cfg, git.New(append(gitOpts, git.WithIncludeStaged(cfg.IncludeStaged))...), tags, args[1:], stdout)
+	default:
+		return fmt.Errorf("%w: %s", errUnknownCommand, args[0])
+	}
+}
+
 func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	ctx := context.Background()
 
-	if err := run(ctx, logger, false); err != nil {
+	var err error
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		err = runCommand(ctx, logger, os.Args[1:], os.Stdout)
+	} else {
+		err = run(ctx, logger, false, os.Args[1:]...)
+	}
+
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
 		logger.Error("error running application", "error", err)
 		os.Exit(1)
 	}
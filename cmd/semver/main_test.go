@@ -67,6 +67,21 @@ func TestRunFunction(t *testing.T) {
 				"CHANGELOG.md",
 			},
 		},
+		{
+			name: "invalid changelog template",
+			setupFiles: func(t *testing.T) {
+				cfg := `{"changelog": {"entryTemplate": "- {{.Short"}}`
+				err := os.WriteFile(filepath.Join(dir, ".semver.json"), []byte(cfg), 0644)
+				if err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+			},
+			expectError: true,
+			unexpectedFiles: []string{
+				"VERSION.md",
+				"CHANGELOG.md",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -110,4 +125,3 @@ func TestRunFunction(t *testing.T) {
 		})
 	}
 }
-
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/tui"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var errNothingToRelease = errors.New("no commits since the last release call for a new version")
+
+// nextRepo is what the next command needs from git.
+type nextRepo interface {
+	git.Service
+	MaintenanceLine(ctx context.Context) (*version.Line, *version.Version, error)
+}
+
+// runNext prints the version the next release would get, for a given bump
+// type or, with --auto, the one the Conventional Commits since the last
+// release tag call for. On a release branch only patch releases of its line
+// are possible.
+func runNext(ctx context.Context, cfg *config.Config, gitSvc nextRepo, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("next", flag.ContinueOnError)
+	auto := fs.Bool("auto", false, "recommend the bump from the commits since the last release tag")
+	bump := fs.String("type", "", "version bump: major, minor or patch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUnknownCommand, fs.Arg(0))
+	}
+	if *auto == (*bump != "") {
+		return fmt.Errorf("%w: next requires either --auto or --type", errUnknownCommand)
+	}
+
+	var opts []version.Option
+	line, latest, err := gitSvc.MaintenanceLine(ctx)
+	if err != nil {
+		return err
+	}
+	if line != nil {
+		opts = append(opts, version.WithLine(*line, latest))
+	}
+	vs := version.NewFileService(cfg.VersionFile, opts...)
+	t := version.Type(*bump)
+	if *auto {
+		tag, err := gitSvc.LatestTag(ctx)
+		if err != nil {
+			return err
+		}
+		commits, err := gitSvc.Log(ctx, tag)
+		if err != nil {
+			return err
+		}
+		cur, err := vs.Read()
+		if err != nil {
+			return err
+		}
+		if t, _ = tui.Recommend(cur, commits, cfg.Changelog.Commits); t == "" {
+			return errNothingToRelease
+		}
+	}
+
+	next, err := vs.Next(t)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, next)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestRunNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		line    *version.Line
+		exclude []string
+		commits []git.Commit
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "auto from features",
+			current: "1.2.3",
+			commits: []git.Commit{{Message: "fix: a"}, {Message: "feat: b"}},
+			args:    []string{"--auto"},
+			want:    "1.3.0\n",
+		},
+		{
+			name:    "auto breaking before 1.0.0",
+			current: "0.4.1",
+			commits: []git.Commit{{Message: "feat!: b"}},
+			args:    []string{"--auto"},
+			want:    "0.5.0\n",
+		},
+		{
+			name:    "explicit type",
+			current: "1.2.3",
+			args:    []string{"--type", "major"},
+			want:    "2.0.0\n",
+		},
+		{
+			name:    "release branch",
+			current: "2.1.0",
+			line:    &version.Line{Major: 1, Minor: 4},
+			commits: []git.Commit{{Message: "fix: a"}},
+			args:    []string{"--auto"},
+			want:    "1.4.3\n",
+		},
+		{
+			name:    "feature on a release branch",
+			current: "2.1.0",
+			line:    &version.Line{Major: 1, Minor: 4},
+			commits: []git.Commit{{Message: "feat: b"}},
+			args:    []string{"--auto"},
+			wantErr: version.ErrMaintenanceLine,
+		},
+		{
+			name:    "nothing to release",
+			current: "1.2.3",
+			commits: []git.Commit{{Message: "docs: a"}},
+			args:    []string{"--auto"},
+			wantErr: errNothingToRelease,
+		},
+		{
+			name:    "excluded types",
+			current: "1.2.3",
+			exclude: []string{"perf"},
+			commits: []git.Commit{{Message: "perf: cache lookups"}},
+			args:    []string{"--auto"},
+			wantErr: errNothingToRelease,
+		},
+		{
+			name:    "merges",
+			current: "1.2.3",
+			commits: []git.Commit{{Message: "fix: a"}, {Message: "feat: merged", Merge: true}},
+			args:    []string{"--auto"},
+			want:    "1.2.4\n",
+		},
+		{
+			name:    "auto or type is required",
+			current: "1.2.3",
+			wantErr: errUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versionFile := filepath.Join(t.TempDir(), "VERSION.md")
+			if err := os.WriteFile(versionFile, []byte(tt.current), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			cfg := &config.Config{VersionFile: versionFile}
+			cfg.Changelog.Commits.ExcludeTypes = tt.exclude
+			err := runNext(context.Background(), cfg, &fakeGit{commits: tt.commits, line: tt.line, lineLatest: &version.Version{Major: 1, Minor: 4, Patch: 2}}, tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runNext() error = %v, want %v", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+
+			data, _ := os.ReadFile(versionFile)
+			if string(data) != tt.current {
+				t.Errorf("version file = %q, want it unchanged", data)
+			}
+		})
+	}
+}
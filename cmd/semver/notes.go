@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/notes"
+)
+
+var errNoNote = errors.New("no release metadata recorded")
+
+// notesRepo is what the notes command needs from git.
+type notesRepo interface {
+	notes.Repo
+	Resolve(ctx context.Context, rev string) (string, error)
+}
+
+// runNotes prints the release metadata recorded on release commits as JSON:
+// every release, newest first, or the release of one commit, given as a
+// revision, tag or version.
+func runNotes(ctx context.Context, logger *slog.Logger, repo notesRepo, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("notes", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: notes takes at most one release", errUnknownCommand)
+	}
+
+	releases, invalid, err := notes.List(ctx, repo)
+	if err != nil {
+		return err
+	}
+	for _, err := range invalid {
+		logger.Warn("skipping note", "error", err)
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if fs.NArg() == 0 {
+		return enc.Encode(releases)
+	}
+
+	commit, err := repo.Resolve(ctx, revision(tags, fs.Arg(0)))
+	if err != nil {
+		return err
+	}
+	for _, r := range releases {
+		if r.Commit == commit {
+			return enc.Encode(r)
+		}
+	}
+	return fmt.Errorf("%w on %s", errNoNote, fs.Arg(0))
+}
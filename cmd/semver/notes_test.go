@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/notes"
+)
+
+func TestRunNotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.0.0"), 0644); err != nil {
+		t.Fatalf("Failed to write version file: %v", err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Release 1.0.0")
+	runGit(t, dir, "tag", "v1.0.0")
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	ctx := context.Background()
+	commits := make(map[string]string)
+	for _, c := range []struct{ message, bump, version string }{
+		{"feat: add export", "minor", "1.1.0"},
+		{"fix: handle empty input", "patch", "1.1.1"},
+	} {
+		runGit(t, dir, "commit", "--allow-empty", "-m", c.message)
+		if err := runCommand(ctx, slog.Default(), []string{"release", "--type", c.bump}, &bytes.Buffer{}); err != nil {
+			t.Fatalf("release error = %v", err)
+		}
+		commits[c.version] = runGit(t, dir, "rev-parse", "HEAD")
+	}
+	// A note written by hand is skipped.
+	runGit(t, dir, "notes", "--ref=refs/notes/semver", "add", "-m", "Reviewed by QA", "v1.0.0")
+
+	tests := []struct {
+		name string
+		args []string
+		// want lists the commit and version of each release printed.
+		want    []string
+		wantErr error
+	}{
+		{name: "all releases", want: []string{commits["1.1.1"], "1.1.1", commits["1.1.0"], "1.1.0"}},
+		{name: "by version", args: []string{"1.1.0"}, want: []string{commits["1.1.0"], "1.1.0"}},
+		{name: "by tag", args: []string{"v1.1.0"}, want: []string{commits["1.1.0"], "1.1.0"}},
+		{name: "by revision", args: []string{"HEAD"}, want: []string{commits["1.1.1"], "1.1.1"}},
+		{name: "release without metadata", args: []string{"v1.0.0"}, wantErr: errNoNote},
+		{name: "too many arguments", args: []string{"v1.1.0", "v1.1.1"}, wantErr: errUnknownCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runCommand(ctx, slog.Default(), append([]string{"notes"}, tt.args...), &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("notes error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var releases []notes.Release
+			if len(tt.args) == 0 {
+				err = json.Unmarshal(out.Bytes(), &releases)
+			} else {
+				releases = make([]notes.Release, 1)
+				err = json.Unmarshal(out.Bytes(), &releases[0])
+			}
+			if err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+			}
+			var got []string
+			for _, r := range releases {
+				got = append(got, r.Commit, r.Version)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("releases = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	if err := runCommand(ctx, slog.Default(), []string{"notes", "1.1.1"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var r notes.Release
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.PreviousVersion != "1.1.0" || r.Type != "patch" || r.ReleasedBy != "Test User <test@example.com>" || r.ReleasedAt.IsZero() ||
+		len(r.Entries) != 1 || r.Entries[0].Summary != "handle empty input" {
+		t.Errorf("release 1.1.1 = %+v", r)
+	}
+}
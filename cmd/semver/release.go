@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/tui"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// runRelease makes a release from the commits since the last release tag
+// without the TUI, for use in CI.
+func runRelease(ctx context.Context, logger *slog.Logger, cfg *config.Config, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("release", flag.ContinueOnError)
+	bump := fs.String("type", "", "version bump: major, minor or patch")
+	tag := fs.Bool("tag", true, "tag the release commit")
+	push := fs.Bool("push", false, "push the release commit and tag")
+	fs.StringVar(&cfg.Push.Remote, "remote", cfg.Push.Remote, "remote to push to")
+	fs.StringVar(&cfg.Push.Branch, "branch", cfg.Push.Branch, "remote branch to push the release commit to")
+	fs.BoolVar(&cfg.IncludeStaged, "include-staged", cfg.IncludeStaged, "commit changes staged before the release along with it")
+	fs.BoolVar(&cfg.ReleaseCommit.SignOff, "s", cfg.ReleaseCommit.SignOff, "add a Signed-off-by trailer to the release commit")
+	migrationFile := fs.String("migration-file", "", "read the migration notes of a major release from `file`")
+	noMigration := fs.Bool("no-migration", false, "make a major release without migration notes")
+	skipChecks(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUnknownCommand, fs.Arg(0))
+	}
+	if *migrationFile != "" && *noMigration {
+		return fmt.Errorf("%w: --migration-file and --no-migration can't be combined", errUnknownCommand)
+	}
+
+	t := version.Type(*bump)
+	if !slices.Contains([]version.Type{version.Major, version.Minor, version.Patch}, t) {
+		return fmt.Errorf("%w: %q", version.ErrInvalidType, *bump)
+	}
+
+	var migration string
+	if *migrationFile != "" {
+		data, err := os.ReadFile(*migrationFile)
+		if err != nil {
+			return fmt.Errorf("reading migration notes: %w", err)
+		}
+		migration = string(data)
+	}
+
+	app, err := tui.New(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	ver, err := app.Release(ctx, tui.ReleaseOptions{
+		Type:        t,
+		Tag:         *tag,
+		Push:        *push,
+		Migration:   migration,
+		NoMigration: *noMigration,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "released %s\n", ver)
+	if *tag {
+		fmt.Fprintf(stdout, "tagged %s\n", tags.Name(ver))
+	}
+	if *push {
+		fmt.Fprintf(stdout, "pushed to %s\n", cfg.Push.Remote)
+	}
+	return nil
+}
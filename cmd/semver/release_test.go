@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/preflight"
+	"github.com/WagnerMatos/semver/internal/tui"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// hotfixBranch releases 1.1.0 on main, then checks out release/1.0 from
+// v1.0.0 with a fix on it.
+func hotfixBranch(t *testing.T, dir string) {
+	if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.1.0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "commit", "-am", "chore(release): v1.1.0")
+	runGit(t, dir, "tag", "v1.1.0")
+	runGit(t, dir, "checkout", "-q", "-b", "release/1.0", "v1.0.0")
+	runGit(t, dir, "commit", "--allow-empty", "-m", "fix: reject empty input")
+}
+
+func TestRunRelease(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	migrationFile := filepath.Join(t.TempDir(), "migration.md")
+	if err := os.WriteFile(migrationFile, []byte("Rename the config file.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		noChanges  bool
+		setup      func(t *testing.T, dir string)
+		wantOutput string
+		wantTags   string
+		// wantEntry defaults to the feature committed on main.
+		wantEntry string
+		// wantCommitted, when set, lists the files of the release commit.
+		wantCommitted string
+		wantErr       error
+	}{
+		{
+			name:       "tag and push",
+			args:       []string{"--type", "minor", "--push"},
+			wantOutput: "released 1.1.0\ntagged v1.1.0\npushed to origin\n",
+			wantTags:   "v1.0.0\nv1.1.0",
+		},
+		{
+			name:       "no push",
+			args:       []string{"--type", "patch"},
+			wantOutput: "released 1.0.1\ntagged v1.0.1\n",
+			wantTags:   "v1.0.0",
+		},
+		{
+			name:      "nothing to release",
+			args:      []string{"--type", "patch"},
+			noChanges: true,
+			wantErr:   tui.ErrNoChanges,
+		},
+		{
+			name: "preflight failure leaves files alone",
+			args: []string{"--type", "minor"},
+			setup: func(t *testing.T, dir string) {
+				// A tag outside the current history, so it isn't the latest.
+				orphan := runGit(t, dir, "commit-tree", "HEAD^{tree}", "-m", "elsewhere")
+				runGit(t, dir, "tag", "v1.1.0", orphan)
+				if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.0.0\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: preflight.ErrFailed,
+		},
+		{
+			name: "staged changes leave files alone",
+			args: []string{"--type", "patch", "--skip-check", "clean"},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "other.txt")
+			},
+			wantErr: git.ErrStagedChanges,
+		},
+		{
+			name: "staged changes included",
+			args: []string{"--type", "patch", "--include-staged"},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "other.txt")
+			},
+			wantOutput:    "released 1.0.1\ntagged v1.0.1\n",
+			wantTags:      "v1.0.0",
+			wantCommitted: "CHANGELOG.md\nVERSION.md\nother.txt",
+		},
+		{
+			name: "skipped check",
+			args: []string{"--type", "patch", "--skip-check", "branch"},
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-b", "feature")
+			},
+			wantOutput: "released 1.0.1\ntagged v1.0.1\n",
+			wantTags:   "v1.0.0",
+		},
+		{
+			name: "go-git backend",
+			args: []string{"--type", "minor", "--push"},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, ".semver.json"), []byte(`{"gitBackend": "go-git"}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantOutput: "released 1.1.0\ntagged v1.1.0\npushed to origin\n",
+			wantTags:   "v1.0.0\nv1.1.0",
+		},
+		{
+			name:       "hotfix on a release branch",
+			args:       []string{"--type", "patch", "--push"},
+			setup:      hotfixBranch,
+			wantOutput: "released 1.0.1\ntagged v1.0.1\npushed to origin\n",
+			wantTags:   "v1.0.0\nv1.0.1",
+			wantEntry:  "### Fixed\n- reject empty input",
+		},
+		{
+			name:    "minor release on a release branch",
+			args:    []string{"--type", "minor"},
+			setup:   hotfixBranch,
+			wantErr: version.ErrMaintenanceLine,
+		},
+		{
+			name: "latest release yanked",
+			args: []string{"--type", "minor"},
+			setup: func(t *testing.T, dir string) {
+				// 1.0.1 shipped the feature and was yanked, so the next
+				// release covers it again.
+				runGit(t, dir, "tag", "v1.0.1")
+				changelog := "# Changelog\n\n## [1.0.1] - 2024-12-24 [YANKED] Broken export\n### Added\n- add export\n"
+				if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte(changelog), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.0.1"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", ".")
+				runGit(t, dir, "commit", "-m", "chore(release): yank v1.0.1")
+			},
+			wantOutput: "released 1.1.0\ntagged v1.1.0\n",
+			wantTags:   "v1.0.0",
+			wantEntry:  "### Added\n- add export by Test User\n\n## [1.0.1]",
+		},
+		{
+			name: "configured release files",
+			args: []string{"--type", "minor"},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"version": "1.0.0"}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "package.json")
+				runGit(t, dir, "commit", "-m", "build: add package.json")
+				// As a version sync would leave it.
+				if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"version": "1.1.0"}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, ".semver.json"), []byte(`{"releaseFiles": ["package.json"]}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantOutput:    "released 1.1.0\ntagged v1.1.0\n",
+			wantTags:      "v1.0.0",
+			wantCommitted: "CHANGELOG.md\nVERSION.md\npackage.json",
+		},
+		{
+			name:       "major with migration notes",
+			args:       []string{"--type", "major", "--migration-file", migrationFile},
+			wantOutput: "released 2.0.0\ntagged v2.0.0\n",
+			wantTags:   "v1.0.0",
+			wantEntry:  "### Migration\nRename the config file.",
+		},
+		{
+			name:       "major without migration notes by choice",
+			args:       []string{"--type", "major", "--no-migration"},
+			wantOutput: "released 2.0.0\ntagged v2.0.0\n",
+			wantTags:   "v1.0.0",
+		},
+		{
+			name:    "major needs migration notes",
+			args:    []string{"--type", "major"},
+			wantErr: tui.ErrMigrationRequired,
+		},
+		{
+			name:    "migration notes and opt-out",
+			args:    []string{"--type", "major", "--migration-file", migrationFile, "--no-migration"},
+			wantErr: errUnknownCommand,
+		},
+		{
+			name:    "type is required",
+			args:    []string{"--push"},
+			wantErr: version.ErrInvalidType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := t.TempDir()
+			runGit(t, remote, "init", "--bare")
+
+			dir := t.TempDir()
+			runGit(t, dir, "init", "-b", "main")
+			runGit(t, dir, "config", "user.name", "Test User")
+			runGit(t, dir, "config", "user.email", "test@example.com")
+			runGit(t, dir, "remote", "add", "origin", remote)
+			if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.0.0"), 0644); err != nil {
+				t.Fatalf("Failed to write version file: %v", err)
+			}
+			runGit(t, dir, "add", ".")
+			runGit(t, dir, "commit", "-m", "Release 1.0.0")
+			runGit(t, dir, "tag", "v1.0.0")
+			runGit(t, dir, "push", "origin", "main", "v1.0.0")
+			if !tt.noChanges {
+				runGit(t, dir, "commit", "--allow-empty", "-m", "feat: add export")
+			}
+
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			before, _ := os.ReadFile(filepath.Join(dir, "VERSION.md"))
+			changelogBefore, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+
+			var out bytes.Buffer
+			err := runCommand(context.Background(), slog.Default(), append([]string{"release"}, tt.args...), &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				after, _ := os.ReadFile(filepath.Join(dir, "VERSION.md"))
+				if !bytes.Equal(after, before) {
+					t.Errorf("VERSION.md changed to %q on error", after)
+				}
+				if after, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md")); !bytes.Equal(after, changelogBefore) {
+					t.Errorf("CHANGELOG.md changed on error:\n%s", after)
+				}
+				return
+			}
+
+			if out.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOutput)
+			}
+			if got := runGit(t, remote, "tag"); got != tt.wantTags {
+				t.Errorf("remote tags = %q, want %q", got, tt.wantTags)
+			}
+			if strings.Contains(tt.wantOutput, "pushed") {
+				if got := runGit(t, remote, "notes", "--ref=refs/notes/semver", "list"); got == "" {
+					t.Error("remote has no release metadata")
+				}
+			}
+			content, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+			if err != nil {
+				t.Fatalf("Failed to read changelog: %v", err)
+			}
+			if tt.wantCommitted != "" {
+				if got := runGit(t, dir, "show", "--name-only", "--format=", "HEAD"); got != tt.wantCommitted {
+					t.Errorf("committed files = %q, want %q", got, tt.wantCommitted)
+				}
+			}
+			if tt.wantEntry == "" {
+				tt.wantEntry = "### Added\n- add export"
+			}
+			if !strings.Contains(string(content), tt.wantEntry) {
+				t.Errorf("changelog =\n%s", content)
+			}
+		})
+	}
+}
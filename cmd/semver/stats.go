@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+// statsRepo is what the stats command needs from git.
+type statsRepo interface {
+	Stats(ctx context.Context, from, to string) (*git.Stats, error)
+}
+
+// runStats reports the commits, authors, first-time contributors and
+// changed files between two releases, given as from..to. Either end may be
+// a version, tag or revision; to defaults to HEAD and an empty from covers
+// the whole history.
+func runStats(ctx context.Context, repo statsRepo, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, to, ok := strings.Cut(fs.Arg(0), "..")
+	if fs.NArg() != 1 || !ok {
+		return fmt.Errorf("%w: stats takes a range such as v1.1.0..v1.2.0", errUnknownCommand)
+	}
+	from = revision(tags, from)
+	if to = revision(tags, to); to == "" {
+		to = "HEAD"
+	}
+
+	stats, err := repo.Stats(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s..%s: %s by %s\n", from, to, count(stats.Commits, "commit"), count(len(stats.Contributors), "author"))
+	fmt.Fprintf(stdout, "%s changed, %s(+), %s(-)\n",
+		count(len(stats.Files), "file"), count(stats.Insertions, "insertion"), count(stats.Deletions, "deletion"))
+
+	if len(stats.Contributors) > 0 {
+		fmt.Fprintf(stdout, "\nAuthors:\n")
+		for _, c := range stats.Contributors {
+			fmt.Fprintf(stdout, "%6d  %s\n", c.Commits, c.Author)
+		}
+	}
+	if first := stats.FirstTime(); len(first) > 0 {
+		fmt.Fprintf(stdout, "\nFirst-time contributors:\n")
+		for _, c := range first {
+			fmt.Fprintf(stdout, "  %s\n", c.Author)
+		}
+	}
+	if len(stats.Files) > 0 {
+		fmt.Fprintf(stdout, "\nFiles:\n")
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, f := range stats.Files {
+			if f.Binary {
+				fmt.Fprintf(w, "  %s\tbinary\n", f.Path)
+			} else {
+				fmt.Fprintf(w, "  %s\t+%d -%d\n", f.Path, f.Insertions, f.Deletions)
+			}
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// revision turns a version into its release tag and leaves anything else,
+// such as a tag or commit, as it is.
+func revision(tags *git.TagFormat, s string) string {
+	if ver, err := parseRelease(tags, s); err == nil {
+		return tags.Name(ver)
+	}
+	return s
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+func TestRunStats(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	commitAs := func(author, file, content string) {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", file)
+		runGit(t, dir, "commit", "-m", "change "+file, "--author", author)
+	}
+	commitAs("Alice <alice@example.com>", "main.go", "package main\n")
+	runGit(t, dir, "tag", "v1.0.0")
+	commitAs("Bob <bob@old.example.com>", "README.md", "# Tool\n")
+	commitAs("Alice <alice@example.com>", "main.go", "package main\n\nfunc main() {}\n")
+	runGit(t, dir, "tag", "v1.1.0")
+	commitAs("Alice <alice@example.com>", "main.go", "package main\n")
+	if err := os.WriteFile(filepath.Join(dir, ".mailmap"), []byte("Bob Smith <bob@example.com> <bob@old.example.com>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput string
+		wantErr    error
+	}{
+		{
+			name: "between releases",
+			args: []string{"1.0.0..1.1.0"},
+			wantOutput: "v1.0.0..v1.1.0: 2 commits by 2 authors\n" +
+				"2 files changed, 3 insertions(+), 0 deletions(-)\n" +
+				"\n" +
+				"Authors:\n" +
+				"     1  Alice <alice@example.com>\n" +
+				"     1  Bob Smith <bob@example.com>\n" +
+				"\n" +
+				"First-time contributors:\n" +
+				"  Bob Smith <bob@example.com>\n" +
+				"\n" +
+				"Files:\n" +
+				"  README.md  +1 -0\n" +
+				"  main.go    +2 -0\n",
+		},
+		{
+			name: "up to HEAD",
+			args: []string{"v1.1.0.."},
+			wantOutput: "v1.1.0..HEAD: 1 commit by 1 author\n" +
+				"1 file changed, 0 insertions(+), 2 deletions(-)\n" +
+				"\n" +
+				"Authors:\n" +
+				"     1  Alice <alice@example.com>\n" +
+				"\n" +
+				"Files:\n" +
+				"  main.go  +0 -2\n",
+		},
+		{
+			name: "empty range",
+			args: []string{"HEAD..HEAD"},
+			wantOutput: "HEAD..HEAD: 0 commits by 0 authors\n" +
+				"0 files changed, 0 insertions(+), 0 deletions(-)\n",
+		},
+		{
+			name:    "not a range",
+			args:    []string{"v1.1.0"},
+			wantErr: errUnknownCommand,
+		},
+		{
+			name:    "missing release",
+			args:    []string{"v0.9.0..v1.0.0"},
+			wantErr: git.ErrLogFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runStats(context.Background(), git.New(), nil, tt.args, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOutput {
+				t.Errorf("output =\n%s\nwant:\n%s", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var errNoReleaseTag = errors.New("no release tag found")
+
+func runTag(ctx context.Context, gitSvc git.Service, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: tag requires a subcommand", errUnknownCommand)
+	}
+
+	switch args[0] {
+	case "verify":
+		return runTagVerify(ctx, gitSvc, tags, args[1:], stdout)
+	default:
+		return fmt.Errorf("%w: tag %s", errUnknownCommand, args[0])
+	}
+}
+
+// runTagVerify checks the signature of a release tag, the latest one
+// reachable from HEAD by default.
+func runTagVerify(ctx context.Context, gitSvc git.Service, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tag verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: tag verify takes at most one tag", errUnknownCommand)
+	}
+
+	tag := fs.Arg(0)
+	if tag == "" {
+		latest, err := gitSvc.LatestTag(ctx)
+		if err != nil {
+			return err
+		}
+		if latest == "" {
+			return errNoReleaseTag
+		}
+		tag = latest
+	} else if ver, err := parseRelease(tags, tag); err == nil {
+		tag = tags.Name(ver)
+	}
+
+	report, err := gitSvc.VerifyTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if report = strings.TrimSpace(report); report != "" {
+		fmt.Fprintln(stdout, report)
+	}
+	fmt.Fprintf(stdout, "tag %s has a valid signature\n", tag)
+	return nil
+}
+
+// parseRelease reads a release given as a version, with or without a
+// leading v, or as its tag.
+func parseRelease(tags *git.TagFormat, arg string) (*version.Version, error) {
+	if ver, ok := tags.Parse(arg); ok {
+		return ver, nil
+	}
+	s := strings.TrimPrefix(arg, "v")
+	ver, err := version.ParseVersion(s)
+	if err != nil || ver.String() != s {
+		return nil, fmt.Errorf("%w: %s", version.ErrInvalidVersion, arg)
+	}
+	return ver, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+func TestRunTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		latestTag string
+		format    string
+		args      []string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "latest tag by default",
+			latestTag: "v1.2.0",
+			args:      []string{"verify"},
+			want:      "v1.2.0",
+		},
+		{
+			name: "version without prefix",
+			args: []string{"verify", "1.1.0"},
+			want: "v1.1.0",
+		},
+		{
+			name:   "version in a custom tag format",
+			format: "{{.Package}}/v{{.Version}}",
+			args:   []string{"verify", "v1.1.0"},
+			want:   "api/v1.1.0",
+		},
+		{
+			name: "any tag name",
+			args: []string{"verify", "nightly"},
+			want: "nightly",
+		},
+		{
+			name:    "no release tag",
+			args:    []string{"verify"},
+			wantErr: errNoReleaseTag,
+		},
+		{
+			name:    "unknown subcommand",
+			args:    []string{"sign"},
+			wantErr: errUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := git.NewTagFormat(tt.format, "api")
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := &fakeGit{latestTag: tt.latestTag}
+			var stdout bytes.Buffer
+			err = runTag(context.Background(), g, tags, tt.args, &stdout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runTag() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(g.verified) != 1 || g.verified[0] != tt.want {
+				t.Errorf("verified tags = %v, want [%s]", g.verified, tt.want)
+			}
+			if want := "Good signature\ntag " + tt.want + " has a valid signature\n"; stdout.String() != want {
+				t.Errorf("output = %q, want %q", stdout.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/notes"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// releaseVersionTrailer is the trailer release commits carry by default.
+const releaseVersionTrailer = "Release-Version"
+
+var (
+	errNotRelease     = errors.New("the last commit is not a release made by semver")
+	errReleasePushed  = errors.New("the release was already pushed")
+	errUnsavedChanges = errors.New("release files have uncommitted changes")
+)
+
+// undoRepo is what the undo command needs from git.
+type undoRepo interface {
+	Resolve(ctx context.Context, rev string) (string, error)
+	IsAncestor(ctx context.Context, ancestor, rev string) (bool, error)
+	Log(ctx context.Context, since string) ([]git.Commit, error)
+	FileAt(ctx context.Context, rev, path string) ([]byte, error)
+	TagExists(ctx context.Context, tag string) (bool, error)
+	RemoteURL(ctx context.Context, name string) (string, error)
+	RemoteRefs(ctx context.Context, remote string) (map[string]string, error)
+	Notes(ctx context.Context, ref string) (map[string]string, error)
+	DeleteTag(ctx context.Context, tag string) error
+	RemoveNote(ctx context.Context, ref, rev string) error
+	ResetSoft(ctx context.Context, rev string) error
+	Add(ctx context.Context, files []string) error
+}
+
+// restore is a release file to put back as it was before the release, or
+// to remove when the release created it.
+type restore struct {
+	path    string
+	content []byte
+	created bool
+}
+
+// runUndo takes back the release commit at HEAD, as long as it hasn't been
+// pushed: it deletes the release tag and metadata note, drops the commit and
+// puts the files the release wrote back as they were. Everything is checked
+// before anything is changed.
+func runUndo(ctx context.Context, cfg *config.Config, repo undoRepo, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	remote := fs.String("remote", cfg.Push.Remote, "remote the release would have been pushed to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUnknownCommand, fs.Arg(0))
+	}
+
+	head, err := repo.Resolve(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+	parent, err := repo.Resolve(ctx, "HEAD^")
+	if err != nil {
+		return fmt.Errorf("%w: it has no parent", errNotRelease)
+	}
+
+	ver, err := releaseAt(ctx, cfg, repo, tags, head, parent)
+	if err != nil {
+		return err
+	}
+	tag := tags.Name(ver)
+	tagged, err := repo.TagExists(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if tagged {
+		at, err := repo.Resolve(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if at != head {
+			return fmt.Errorf("%w: tag %s is on another commit", errNotRelease, tag)
+		}
+	}
+
+	// A repository without the remote has nowhere to have pushed to.
+	if _, err := repo.RemoteURL(ctx, *remote); err == nil {
+		refs, err := repo.RemoteRefs(ctx, *remote)
+		if err != nil {
+			return fmt.Errorf("cannot tell whether %s was pushed: %w", ver, err)
+		}
+		where, err := published(ctx, repo, refs, head, tag)
+		if err != nil {
+			return fmt.Errorf("cannot tell whether %s was pushed, fetch %s first: %w", ver, *remote, err)
+		}
+		if where != "" {
+			return fmt.Errorf("%w: %s is on %s as %s. Undoing it would rewrite history others may have fetched; "+
+				"fix the problem in a follow-up release instead, and yank %s if it must not be used",
+				errReleasePushed, ver, *remote, where, ver)
+		}
+	}
+
+	restores, err := releaseChanges(ctx, cfg, repo, head, parent)
+	if err != nil {
+		return err
+	}
+	recorded, err := repo.Notes(ctx, notes.Ref)
+	if err != nil {
+		return err
+	}
+	_, noted := recorded[head]
+
+	if tagged {
+		if err := repo.DeleteTag(ctx, tag); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "deleted tag %s\n", tag)
+	}
+	if noted {
+		if err := repo.RemoveNote(ctx, notes.Ref, head); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "removed the release metadata note\n")
+	}
+	if err := repo.ResetSoft(ctx, parent); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "dropped release commit %s; HEAD is now %s\n", short(head), short(parent))
+
+	paths := make([]string, 0, len(restores))
+	for _, r := range restores {
+		name := filepath.Base(r.path)
+		if r.created {
+			if err := os.Remove(r.path); err != nil {
+				return err
+			}
+			fmt.Fprintf(stdout, "removed %s, which the release created\n", name)
+		} else {
+			if err := os.WriteFile(r.path, r.content, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(stdout, "restored %s as of %s\n", name, short(parent))
+		}
+		paths = append(paths, r.path)
+	}
+	if err := repo.Add(ctx, paths); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "undid release %s\n", ver)
+	return nil
+}
+
+// releaseAt returns the version released by the commit head: its version
+// file must hold a new version, its changelog a section for it and its
+// message must name it.
+func releaseAt(ctx context.Context, cfg *config.Config, repo undoRepo, tags *git.TagFormat, head, parent string) (*version.Version, error) {
+	versionFile := filepath.Base(cfg.VersionFile)
+	ver, err := versionAt(ctx, repo, head, cfg.VersionFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read %s: %v", errNotRelease, versionFile, err)
+	}
+	if prev, err := versionAt(ctx, repo, parent, cfg.VersionFile); err == nil && prev.Compare(ver) == 0 {
+		return nil, fmt.Errorf("%w: it doesn't change %s", errNotRelease, versionFile)
+	}
+
+	changelogFile := filepath.Base(cfg.ChangelogFile)
+	data, err := repo.FileAt(ctx, head, cfg.ChangelogFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read %s: %v", errNotRelease, changelogFile, err)
+	}
+	if _, err := changelog.Notes(data, ver.String()); err != nil {
+		return nil, fmt.Errorf("%w: %s has no section for %s", errNotRelease, changelogFile, ver)
+	}
+
+	commits, err := repo.Log(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) != 1 || !releaseMessage(cfg.ReleaseCommit, commits[0].Message, ver.String(), tags.Name(ver)) {
+		return nil, fmt.Errorf("%w: its message doesn't name %s", errNotRelease, ver)
+	}
+	return ver, nil
+}
+
+// releaseMessage reports whether message is that of the release commit of
+// ver, made with cfg: it carries the Release-Version trailer when one is
+// configured, and otherwise names the version or tag in its header.
+func releaseMessage(cfg config.ReleaseCommitConfig, message, ver, tag string) bool {
+	for _, tr := range cfg.Trailers {
+		if tr.Token != releaseVersionTrailer {
+			continue
+		}
+		for _, line := range strings.Split(message, "\n") {
+			token, value, ok := strings.Cut(line, ":")
+			value = strings.TrimSpace(value)
+			if ok && strings.EqualFold(token, releaseVersionTrailer) && (value == ver || value == tag) {
+				return true
+			}
+		}
+		return false
+	}
+	header, _, _ := strings.Cut(message, "\n")
+	return strings.Contains(header, ver)
+}
+
+func versionAt(ctx context.Context, repo undoRepo, rev, path string) (*version.Version, error) {
+	data, err := repo.FileAt(ctx, rev, path)
+	if err != nil {
+		return nil, err
+	}
+	return version.ParseVersion(strings.TrimSpace(string(data)))
+}
+
+// published returns the ref on the remote that holds the release, or "".
+// A ref holds it when it is the release tag or has the release commit in its
+// history, as a branch pushed with later commits on top does.
+func published(ctx context.Context, repo undoRepo, refs map[string]string, head, tag string) (string, error) {
+	if _, ok := refs["refs/tags/"+tag]; ok {
+		return "tag " + tag, nil
+	}
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		hash := refs[name]
+		if hash != head {
+			contains, err := repo.IsAncestor(ctx, head, hash)
+			if err != nil {
+				return "", err
+			}
+			if !contains {
+				continue
+			}
+		}
+		if branch, ok := strings.CutPrefix(name, "refs/heads/"); ok {
+			return "branch " + branch, nil
+		}
+		return "tag " + strings.TrimPrefix(name, "refs/tags/"), nil
+	}
+	return "", nil
+}
+
+// releaseChanges lists the release files the commit head changed, with
+// their content before it. Files edited since the release would lose those
+// edits, so they stop the undo.
+func releaseChanges(ctx context.Context, cfg *config.Config, repo undoRepo, head, parent string) ([]restore, error) {
+	files := []string{cfg.VersionFile, cfg.ChangelogFile}
+	if cfg.UpgradingFile != "" {
+		files = append(files, cfg.UpgradingFile)
+	}
+	files = append(files, cfg.ReleaseFiles...)
+
+	var restores []restore
+	for _, path := range files {
+		released, err := repo.FileAt(ctx, head, path)
+		if err != nil {
+			// Not part of the release.
+			continue
+		}
+		if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, released) {
+			return nil, fmt.Errorf("%w: %s; commit or discard them first", errUnsavedChanges, filepath.Base(path))
+		}
+
+		before, err := repo.FileAt(ctx, parent, path)
+		if err != nil {
+			restores = append(restores, restore{path: path, created: true})
+		} else if !bytes.Equal(before, released) {
+			restores = append(restores, restore{path: path, content: before})
+		}
+	}
+	return restores, nil
+}
+
+func short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
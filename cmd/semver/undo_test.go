@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/config"
+)
+
+func TestRunUndo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	tests := []struct {
+		name        string
+		releaseArgs []string
+		// before runs before the release and after once it is made.
+		before func(t *testing.T, dir string)
+		after  func(t *testing.T, dir string)
+		// wantOutput is formatted with the release commit and its parent.
+		wantOutput    string
+		wantChangelog bool
+		wantErr       error
+	}{
+		{
+			name:        "unpushed release",
+			releaseArgs: []string{"--type", "minor"},
+			wantOutput: "deleted tag v1.1.0\n" +
+				"removed the release metadata note\n" +
+				"dropped release commit %[1]s; HEAD is now %[2]s\n" +
+				"restored VERSION.md as of %[2]s\n" +
+				"removed CHANGELOG.md, which the release created\n" +
+				"undid release 1.1.0\n",
+		},
+		{
+			name:        "existing changelog",
+			releaseArgs: []string{"--type", "patch"},
+			before: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte("# Changelog\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "CHANGELOG.md")
+				runGit(t, dir, "commit", "-m", "docs: start a changelog")
+			},
+			wantOutput: "deleted tag v1.0.1\n" +
+				"removed the release metadata note\n" +
+				"dropped release commit %[1]s; HEAD is now %[2]s\n" +
+				"restored VERSION.md as of %[2]s\n" +
+				"restored CHANGELOG.md as of %[2]s\n" +
+				"undid release 1.0.1\n",
+			wantChangelog: true,
+		},
+		{
+			name:        "untagged release without a remote",
+			releaseArgs: []string{"--type", "minor", "--tag=false"},
+			after: func(t *testing.T, dir string) {
+				runGit(t, dir, "remote", "remove", "origin")
+			},
+			wantOutput: "removed the release metadata note\n" +
+				"dropped release commit %[1]s; HEAD is now %[2]s\n" +
+				"restored VERSION.md as of %[2]s\n" +
+				"removed CHANGELOG.md, which the release created\n" +
+				"undid release 1.1.0\n",
+		},
+		{
+			name:        "pushed release",
+			releaseArgs: []string{"--type", "minor", "--push"},
+			wantErr:     errReleasePushed,
+		},
+		{
+			name:        "tag pushed on its own",
+			releaseArgs: []string{"--type", "minor"},
+			after: func(t *testing.T, dir string) {
+				runGit(t, dir, "push", "origin", "v1.1.0")
+			},
+			wantErr: errReleasePushed,
+		},
+		{
+			name:        "pushed with a later commit",
+			releaseArgs: []string{"--type", "minor"},
+			after: func(t *testing.T, dir string) {
+				runGit(t, dir, "commit", "--allow-empty", "-m", "fix: follow-up")
+				runGit(t, dir, "push", "origin", "main")
+				runGit(t, dir, "reset", "--hard", "HEAD^")
+			},
+			wantErr: errReleasePushed,
+		},
+		{
+			name:        "not made by semver",
+			releaseArgs: []string{"--type", "minor", "--tag=false"},
+			after: func(t *testing.T, dir string) {
+				runGit(t, dir, "commit", "--amend", "-m", "chore: bump the version by hand")
+			},
+			wantErr: errNotRelease,
+		},
+		{
+			name:        "commit after the release",
+			releaseArgs: []string{"--type", "minor"},
+			after: func(t *testing.T, dir string) {
+				runGit(t, dir, "commit", "--allow-empty", "-m", "fix: follow-up")
+			},
+			wantErr: errNotRelease,
+		},
+		{
+			name:        "release files edited",
+			releaseArgs: []string{"--type", "minor"},
+			after: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.2.0"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: errUnsavedChanges,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := t.TempDir()
+			runGit(t, remote, "init", "--bare")
+
+			dir := t.TempDir()
+			runGit(t, dir, "init", "-b", "main")
+			runGit(t, dir, "config", "user.name", "Test User")
+			runGit(t, dir, "config", "user.email", "test@example.com")
+			runGit(t, dir, "remote", "add", "origin", remote)
+			if err := os.WriteFile(filepath.Join(dir, "VERSION.md"), []byte("1.0.0"), 0644); err != nil {
+				t.Fatalf("Failed to write version file: %v", err)
+			}
+			runGit(t, dir, "add", ".")
+			runGit(t, dir, "commit", "-m", "Release 1.0.0")
+			runGit(t, dir, "tag", "v1.0.0")
+			runGit(t, dir, "push", "origin", "main", "v1.0.0")
+			runGit(t, dir, "commit", "--allow-empty", "-m", "feat: add export")
+			if tt.before != nil {
+				tt.before(t, dir)
+			}
+			parent := runGit(t, dir, "rev-parse", "HEAD")
+			changelogBefore, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+			ctx := context.Background()
+			if err := runCommand(ctx, slog.Default(), append([]string{"release"}, tt.releaseArgs...), &bytes.Buffer{}); err != nil {
+				t.Fatalf("release error = %v", err)
+			}
+			head := runGit(t, dir, "rev-parse", "HEAD")
+			if tt.after != nil {
+				tt.after(t, dir)
+			}
+			tagsBefore := runGit(t, dir, "tag")
+
+			var out bytes.Buffer
+			err := runCommand(ctx, slog.Default(), []string{"undo"}, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("undo error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if tags := runGit(t, dir, "tag"); tags != tagsBefore {
+					t.Errorf("tags = %q on error, want %q", tags, tagsBefore)
+				}
+				if out.Len() > 0 {
+					t.Errorf("output = %q on error", out.String())
+				}
+				return
+			}
+
+			if want := fmt.Sprintf(tt.wantOutput, head[:7], parent[:7]); out.String() != want {
+				t.Errorf("output =\n%s\nwant:\n%s", out.String(), want)
+			}
+			if got := runGit(t, dir, "rev-parse", "HEAD"); got != parent {
+				t.Errorf("HEAD = %s, want %s", got, parent)
+			}
+			if notes := runGit(t, dir, "notes", "--ref=refs/notes/semver", "list"); notes != "" {
+				t.Errorf("notes = %q, want none", notes)
+			}
+			if tags := runGit(t, dir, "tag"); tags != "v1.0.0" {
+				t.Errorf("tags = %q, want v1.0.0", tags)
+			}
+			if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+				t.Errorf("status = %q, want a clean tree", status)
+			}
+			if data, _ := os.ReadFile(filepath.Join(dir, "VERSION.md")); string(data) != "1.0.0" {
+				t.Errorf("VERSION.md = %q, want 1.0.0", data)
+			}
+			changelogAfter, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+			if tt.wantChangelog != (err == nil) || !bytes.Equal(changelogAfter, changelogBefore) {
+				t.Errorf("CHANGELOG.md = %q, %v, want %q", changelogAfter, err, changelogBefore)
+			}
+		})
+	}
+}
+
+func TestReleaseMessage(t *testing.T) {
+	trailer := config.ReleaseCommitConfig{Trailers: []config.TrailerConfig{{Token: "Release-Version", Value: "{{.Version}}"}}}
+	tests := []struct {
+		name    string
+		cfg     config.ReleaseCommitConfig
+		message string
+		want    bool
+	}{
+		{name: "trailer", cfg: trailer, message: "chore(release): v1.1.0\n\nRelease-Version: 1.1.0", want: true},
+		{name: "trailer with the tag", cfg: trailer, message: "Release\n\nRelease-Version: v1.1.0", want: true},
+		{name: "trailer of another version", cfg: trailer, message: "chore(release): v1.1.0\n\nRelease-Version: 1.0.0"},
+		{name: "no trailer", cfg: trailer, message: "chore(release): v1.1.0"},
+		{name: "header without a trailer configured", message: "Release 1.1.0\n\n- Added: export", want: true},
+		{name: "version in the body only", message: "Bump\n\n1.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := releaseMessage(tt.cfg, tt.message, "1.1.0", "v1.1.0"); got != tt.want {
+				t.Errorf("releaseMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/gomod"
+)
+
+var errMissingReason = errors.New("a reason is required")
+
+// runYank marks a release as yanked in the changelog, retracts it in go.mod
+// when the project is a Go module, and commits both.
+func runYank(ctx context.Context, cfg *config.Config, gitSvc git.Service, tags *git.TagFormat, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("yank", flag.ContinueOnError)
+	reason := fs.String("reason", "", "why the release was yanked")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: yank requires a version", errUnknownCommand)
+	}
+	// Accept flags after the version too, as in "yank 1.2.0 --reason ...".
+	arg := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUnknownCommand, fs.Arg(0))
+	}
+
+	*reason = strings.TrimSpace(*reason)
+	if *reason == "" {
+		return errMissingReason
+	}
+
+	ver, err := parseRelease(tags, arg)
+	if err != nil {
+		return err
+	}
+	tag := tags.Name(ver)
+
+	// Build both files in memory and check that the commit can be made
+	// before writing either, so that a failure leaves them alone.
+	src, err := os.ReadFile(cfg.ChangelogFile)
+	if err != nil {
+		return fmt.Errorf("reading changelog: %w", err)
+	}
+	yanked, err := changelog.Yank(src, ver.String(), *reason)
+	if err != nil {
+		return err
+	}
+	files := []string{cfg.ChangelogFile}
+
+	// Module versions are always vX.Y.Z, even when tags carry a component
+	// prefix.
+	modVersion := "v" + ver.String()
+	mod, err := os.ReadFile(gomod.FileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading go.mod: %w", err)
+	}
+	var retracted []byte
+	if err == nil {
+		if retracted, err = gomod.Retract(mod, modVersion, *reason); err != nil {
+			return err
+		}
+		files = append(files, gomod.FileName)
+	}
+
+	if err := gitSvc.CheckStaged(ctx, files); err != nil {
+		return err
+	}
+
+	if retracted != nil {
+		if err := os.WriteFile(gomod.FileName, retracted, 0644); err != nil {
+			return fmt.Errorf("writing go.mod: %w", err)
+		}
+	}
+	if err := os.WriteFile(cfg.ChangelogFile, yanked, 0644); err != nil {
+		if retracted != nil {
+			// Best effort: put go.mod back as it was.
+			os.WriteFile(gomod.FileName, mod, 0644)
+		}
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	fmt.Fprintf(stdout, "marked %s as yanked in %s\n", ver, cfg.ChangelogFile)
+	if retracted != nil {
+		fmt.Fprintf(stdout, "retracted %s in %s\n", modVersion, gomod.FileName)
+	}
+
+	// A Conventional Commit, so the commit-msg hook accepts it.
+	message := fmt.Sprintf("chore(release): yank %s\n\n%s", tag, *reason)
+	if err := gitSvc.Commit(ctx, message, files); err != nil {
+		return err
+	}
+	return nil
+}
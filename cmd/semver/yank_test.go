@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/gomod"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// fakeGit records commits and verified tags instead of running git.
+type fakeGit struct {
+	stagedErr error
+	messages  []string
+	files     [][]string
+	latestTag string
+	commits   []git.Commit
+	verified  []string
+	// line and lineLatest are the maintenance line checked out, if any.
+	line       *version.Line
+	lineLatest *version.Version
+}
+
+func (g *fakeGit) Commit(ctx context.Context, message string, files []string) error {
+	g.messages = append(g.messages, message)
+	g.files = append(g.files, files)
+	return nil
+}
+
+func (g *fakeGit) CheckStaged(context.Context, []string) error         { return g.stagedErr }
+func (g *fakeGit) Tag(context.Context, *version.Version, string) error { return nil }
+func (g *fakeGit) VerifyTag(ctx context.Context, tag string) (string, error) {
+	g.verified = append(g.verified, tag)
+	return "Good signature", nil
+}
+
+func (g *fakeGit) Push(context.Context, string, string, string) error { return nil }
+func (g *fakeGit) RemoteURL(context.Context, string) (string, error)  { return "", nil }
+func (g *fakeGit) LatestTag(context.Context) (string, error)          { return g.latestTag, nil }
+func (g *fakeGit) Log(context.Context, string) ([]git.Commit, error)  { return g.commits, nil }
+func (g *fakeGit) Author(context.Context) (string, error)             { return "", nil }
+
+func (g *fakeGit) MaintenanceLine(context.Context) (*version.Line, *version.Version, error) {
+	return g.line, g.lineLatest, nil
+}
+
+func TestRunYank(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	const changelogContent = "## [1.1.0] - 2024-12-24\n### Fixed\n- Fix loader\n"
+
+	tests := []struct {
+		name  string
+		goMod bool
+		// retracted lists v1.1.0 as retracted in go.mod already.
+		retracted     bool
+		format        string
+		args          []string
+		wantChangelog string
+		wantGoMod     string
+		// wantTag defaults to v1.1.0.
+		wantTag string
+		// stagedErr is what checking for staged changes reports.
+		stagedErr error
+		wantErr   error
+	}{
+		{
+			name:          "go module",
+			goMod:         true,
+			args:          []string{"v1.1.0", "--reason", "Breaks the config loader"},
+			wantChangelog: "## [1.1.0] - 2024-12-24 [YANKED] Breaks the config loader\n",
+			wantGoMod:     "// Breaks the config loader\nretract v1.1.0\n",
+		},
+		{
+			name:          "component tags",
+			goMod:         true,
+			format:        "{{.Package}}/v{{.Version}}",
+			args:          []string{"api/v1.1.0", "--reason", "Breaks the config loader"},
+			wantChangelog: "## [1.1.0] - 2024-12-24 [YANKED] Breaks the config loader\n",
+			wantGoMod:     "// Breaks the config loader\nretract v1.1.0\n",
+			wantTag:       "api/v1.1.0",
+		},
+		{
+			name:          "not a go module",
+			args:          []string{"-reason=Breaks the config loader", "1.1.0"},
+			wantChangelog: "## [1.1.0] - 2024-12-24 [YANKED] Breaks the config loader\n",
+		},
+		{
+			name:      "already retracted",
+			goMod:     true,
+			retracted: true,
+			args:      []string{"1.1.0", "--reason", "x"},
+			wantErr:   gomod.ErrAlreadyRetracted,
+		},
+		{
+			name:    "not in the changelog",
+			goMod:   true,
+			args:    []string{"1.2.0", "--reason", "x"},
+			wantErr: changelog.ErrReleaseNotFound,
+		},
+		{
+			name:      "other changes staged",
+			goMod:     true,
+			args:      []string{"1.1.0", "--reason", "x"},
+			stagedErr: git.ErrStagedChanges,
+			wantErr:   git.ErrStagedChanges,
+		},
+		{
+			name:    "reason is required",
+			args:    []string{"1.1.0"},
+			wantErr: errMissingReason,
+		},
+		{
+			name:    "invalid version",
+			args:    []string{"1.1", "--reason", "x"},
+			wantErr: version.ErrInvalidVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+			changelogFile := filepath.Join(dir, "CHANGELOG.md")
+			if err := os.WriteFile(changelogFile, []byte(changelogContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			goMod := "module example.com/m\n\ngo 1.23\n"
+			if tt.retracted {
+				goMod += "\nretract v1.1.0\n"
+			}
+			if tt.goMod {
+				if err := os.WriteFile("go.mod", []byte(goMod), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg := &config.Config{ChangelogFile: changelogFile}
+			tags, err := git.NewTagFormat(tt.format, "api")
+			if err != nil {
+				t.Fatal(err)
+			}
+			gitSvc := &fakeGit{stagedErr: tt.stagedErr}
+			err = runYank(context.Background(), cfg, gitSvc, tags, tt.args, &bytes.Buffer{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runYank() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(gitSvc.messages) > 0 {
+					t.Errorf("committed on error: %q", gitSvc.messages)
+				}
+				if data, _ := os.ReadFile(changelogFile); string(data) != changelogContent {
+					t.Errorf("changelog changed on error:\n%s", data)
+				}
+				if data, _ := os.ReadFile("go.mod"); tt.goMod && string(data) != goMod {
+					t.Errorf("go.mod changed on error:\n%s", data)
+				}
+				return
+			}
+
+			data, _ := os.ReadFile(changelogFile)
+			if !strings.HasPrefix(string(data), tt.wantChangelog) {
+				t.Errorf("changelog =\n%s\nwant prefix:\n%s", data, tt.wantChangelog)
+			}
+			if tt.goMod {
+				data, _ := os.ReadFile("go.mod")
+				if !strings.HasSuffix(string(data), tt.wantGoMod) {
+					t.Errorf("go.mod =\n%s\nwant suffix:\n%s", data, tt.wantGoMod)
+				}
+			}
+
+			if tt.wantTag == "" {
+				tt.wantTag = "v1.1.0"
+			}
+			want := []string{"chore(release): yank " + tt.wantTag + "\n\nBreaks the config loader"}
+			if strings.Join(gitSvc.messages, "|") != strings.Join(want, "|") {
+				t.Errorf("commits = %q, want %q", gitSvc.messages, want)
+			}
+			wantFiles := []string{changelogFile}
+			if tt.goMod {
+				wantFiles = append(wantFiles, "go.mod")
+			}
+			if len(gitSvc.files) != 1 || !slices.Equal(gitSvc.files[0], wantFiles) {
+				t.Errorf("committed files = %q, want %q", gitSvc.files, wantFiles)
+			}
+		})
+	}
+}
+
+func TestRunYank_SignOff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	files := map[string]string{
+		"CHANGELOG.md": "# Changelog\n\n## [1.0.0] - 2024-12-23\n### Added\n- Initial commit\n",
+		".semver.json": `{"releaseCommit": {"signOff": true}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Release 1.0.0")
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	if err := runCommand(context.Background(), slog.Default(), []string{"yank", "1.0.0", "--reason", "Broken"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("yank error = %v", err)
+	}
+	if msg := runGit(t, dir, "log", "-1", "--format=%B"); !strings.HasSuffix(msg, "Signed-off-by: Test User <test@example.com>") {
+		t.Errorf("yank commit message = %q, want it signed off", msg)
+	}
+}
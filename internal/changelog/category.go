@@ -0,0 +1,112 @@
+package changelog
+
+import (
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// Category is a Keep a Changelog section heading.
+type Category string
+
+const (
+	Added      Category = "Added"
+	Changed    Category = "Changed"
+	Deprecated Category = "Deprecated"
+	Removed    Category = "Removed"
+	Fixed      Category = "Fixed"
+	Security   Category = "Security"
+)
+
+var Categories = []Category{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// Migration holds free-form notes on upgrading across a breaking release.
+// Its section contains text rather than entries.
+const Migration Category = "Migration"
+
+// Contributors credits the authors of a release. Like Migration, its
+// section contains text rather than entries.
+const Contributors Category = "Contributors"
+
+// legacyCategories are the bump-type headings written by earlier releases of
+// this tool. They are still accepted when reading a changelog.
+var legacyCategories = []Category{"Major", "Minor", "Patch"}
+
+// LookupCategory returns the canonical spelling of a section name and
+// whether it is known at all. Matching ignores case.
+func LookupCategory(name string) (Category, bool) {
+	for _, list := range [][]Category{Categories, legacyCategories, {Migration, Contributors}} {
+		for _, c := range list {
+			if strings.EqualFold(string(c), strings.TrimSpace(name)) {
+				return c, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DefaultCategory is the category suggested for a new entry in a release of
+// the given type.
+func DefaultCategory(t version.Type) Category {
+	switch t {
+	case version.Major:
+		return Changed
+	case version.Patch:
+		return Fixed
+	default:
+		return Added
+	}
+}
+
+// groupByCategory groups entries in the canonical category order, followed
+// by any custom categories in the order they first appear. Entries without a
+// category fall back to fallback. The order of entries within a category is
+// preserved.
+func groupByCategory(entries []Entry, fallback Category) ([]Category, map[Category][]Entry) {
+	groups := make(map[Category][]Entry)
+	var custom []Category
+	for _, e := range entries {
+		c := e.Category
+		if c == "" {
+			c = fallback
+		}
+		if known, ok := LookupCategory(string(c)); ok {
+			c = known
+		} else if _, seen := groups[c]; !seen {
+			custom = append(custom, c)
+		}
+		groups[c] = append(groups[c], e)
+	}
+
+	var order []Category
+	for _, list := range [][]Category{Categories, legacyCategories, custom} {
+		for _, c := range list {
+			if len(groups[c]) > 0 {
+				order = append(order, c)
+			}
+		}
+	}
+	return order, groups
+}
+
+// commitCategories maps Conventional Commit types to changelog categories.
+var commitCategories = map[string]Category{
+	"feat":      Added,
+	"fix":       Fixed,
+	"perf":      Changed,
+	"refactor":  Changed,
+	"docs":      Changed,
+	"revert":    Removed,
+	"remove":    Removed,
+	"deprecate": Deprecated,
+	"security":  Security,
+}
+
+// CommitCategory returns the category for a Conventional Commit type.
+// Unknown types are listed as Changed.
+func CommitCategory(commitType string) Category {
+	if c, ok := commitCategories[strings.ToLower(commitType)]; ok {
+		return c
+	}
+	return Changed
+}
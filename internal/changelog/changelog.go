@@ -1,43 +1,257 @@
 package changelog
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
+	"github.com/WagnerMatos/semver/internal/clock"
 	"github.com/WagnerMatos/semver/internal/version"
 )
 
 type Service interface {
-	Update(version.Version, version.Type, string, string) error
+	Update(v version.Version, t version.Type, entries []Entry, migration string, contributors []Contributor) error
+}
+
+// Contributor is an author credited in the Contributors section of a
+// release.
+type Contributor struct {
+	Name string
+	// FirstTime marks an author whose first contribution is in the release.
+	FirstTime bool
 }
 
 type FileService struct {
-	filepath string
+	filepath   string
+	upgrading  string
+	clock      clock.Clock
+	dateLayout string
+	links      *Links
+	refs       *RefURLs
+	templates  *Templates
 }
 
-func New(filepath string) *FileService {
-	return &FileService{filepath: filepath}
+type Option func(*FileService)
+
+// WithTemplates replaces the default heading and entry format.
+func WithTemplates(t *Templates) Option {
+	return func(s *FileService) {
+		s.templates = t
+	}
 }
 
-func (s *FileService) Update(v version.Version, t version.Type, shortDesc, longDesc string) error {
-	f, err := os.OpenFile(s.filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// WithLinks keeps compare-link references at the bottom of the changelog up
+// to date on every release.
+func WithLinks(l *Links) Option {
+	return func(s *FileService) {
+		s.links = l
+	}
+}
+
+// WithRefURLs links the issue and tracker references of entries.
+func WithRefURLs(r *RefURLs) Option {
+	return func(s *FileService) {
+		s.refs = r
+	}
+}
+
+// WithClock sets the clock that dates releases, such as one honouring
+// SOURCE_DATE_EPOCH. Defaults to the current time in UTC.
+func WithClock(c clock.Clock) Option {
+	return func(s *FileService) {
+		s.clock = c
+	}
+}
+
+// WithDateLayout formats release dates with a Go time layout instead of
+// DefaultDateLayout. An empty layout keeps the default.
+func WithDateLayout(layout string) Option {
+	return func(s *FileService) {
+		if layout != "" {
+			s.dateLayout = layout
+		}
+	}
+}
+
+// WithUpgrading also adds the migration notes of each release to the
+// upgrade guide at path, one section per major version.
+func WithUpgrading(path string) Option {
+	return func(s *FileService) {
+		s.upgrading = path
+	}
+}
+
+func New(filepath string, opts ...Option) *FileService {
+	s := &FileService{
+		filepath:   filepath,
+		clock:      clock.Wall,
+		dateLayout: DefaultDateLayout,
+		templates:  defaultTemplates,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Update writes a release with all of its entries under a single heading,
+// grouped into one section per category. Migration notes and contributors,
+// if any, follow in sections of their own.
+func (s *FileService) Update(v version.Version, t version.Type, entries []Entry, migration string, contributors []Contributor) error {
+	src, err := os.ReadFile(s.filepath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading changelog: %w", err)
+	}
+
+	cl, err := Parse(bytes.NewReader(src))
 	if err != nil {
-		return fmt.Errorf("opening changelog: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	entry := fmt.Sprintf("\n## [%s] - %s\n", v.String(), time.Now().Format("2006-01-02"))
-	entry += fmt.Sprintf("### %s\n", strings.Title(string(t)))
-	entry += fmt.Sprintf("- %s\n", shortDesc)
-	if longDesc != "" {
-		entry += fmt.Sprintf("  %s\n", longDesc)
+	data := ReleaseData{
+		Version:         v.String(),
+		PreviousVersion: previousVersion(cl, &v),
+		Date:            s.clock.Now().Format(s.dateLayout),
+		Type:            t,
 	}
 
-	if _, err := f.WriteString(entry); err != nil {
+	migration = strings.TrimSpace(migration)
+	block, err := s.render(data, entries, migration, contributors)
+	if err != nil {
+		return err
+	}
+
+	out, err := s.insert(src, block)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.filepath, out, 0644); err != nil {
 		return fmt.Errorf("writing changelog: %w", err)
 	}
 
+	if s.upgrading != "" && migration != "" {
+		return addUpgrading(s.upgrading, v, migration)
+	}
+
 	return nil
 }
+
+func (s *FileService) render(data ReleaseData, entries []Entry, migration string, contributors []Contributor) ([]string, error) {
+	block, err := s.templates.Heading(data)
+	if err != nil {
+		return nil, err
+	}
+
+	order, groups := groupByCategory(entries, DefaultCategory(data.Type))
+	for _, c := range order {
+		section, err := s.templates.Section(SectionData{ReleaseData: data, Category: c})
+		if err != nil {
+			return nil, err
+		}
+		block = append(block, section...)
+
+		for _, e := range groups[c] {
+			lines, err := s.templates.Entry(EntryData{
+				ReleaseData: data,
+				Category:    c,
+				Short:       strings.Join(strings.Fields(e.Short), " "),
+				Long:        e.Long,
+				Author:      e.Author,
+				Commit:      e.Commit,
+				Refs:        s.refs.Resolve(e.Refs),
+			})
+			if err != nil {
+				return nil, err
+			}
+			block = append(block, lines...)
+		}
+	}
+
+	if migration != "" {
+		section, err := s.templates.Section(SectionData{ReleaseData: data, Category: Migration})
+		if err != nil {
+			return nil, err
+		}
+		block = append(block, section...)
+		block = append(block, strings.Split(migration, "\n")...)
+	}
+
+	if len(contributors) > 0 {
+		section, err := s.templates.Section(SectionData{ReleaseData: data, Category: Contributors})
+		if err != nil {
+			return nil, err
+		}
+		block = append(block, section...)
+		for _, c := range contributors {
+			line := "- " + c.Name
+			if c.FirstTime {
+				line += " (first contribution)"
+			}
+			block = append(block, line)
+		}
+	}
+	return block, nil
+}
+
+// previousVersion returns the highest release in cl below v, or "".
+func previousVersion(cl *Changelog, v *version.Version) string {
+	var prev *version.Version
+	_, versions := releaseVersions(cl)
+	for _, other := range versions {
+		if other.Compare(v) < 0 && (prev == nil || other.Compare(prev) > 0) {
+			prev = other
+		}
+	}
+	if prev == nil {
+		return ""
+	}
+	return prev.String()
+}
+
+// insert adds a release block to src, following the order the changelog
+// already uses: below Unreleased in a newest-first changelog, after the last
+// release in an oldest-first one. The link footer is regenerated afterwards.
+func (s *FileService) insert(src []byte, block []string) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	head, blocks, foot := releaseBlocks(cl, splitLines(src))
+
+	_, versions := releaseVersions(cl)
+	if descending(versions) {
+		at := 0
+		for at < len(cl.Releases) && cl.Releases[at].Version == Unreleased {
+			at++
+		}
+		blocks = append(blocks[:at], append([][]string{block}, blocks[at:]...)...)
+	} else {
+		blocks = append(blocks, block)
+	}
+
+	out := assemble(head, blocks, foot)
+	if s.links == nil {
+		return out, nil
+	}
+
+	return s.relink(out)
+}
+
+func (s *FileService) relink(src []byte) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	foot, err := s.links.footer(cl)
+	if err != nil {
+		return nil, err
+	}
+
+	head, blocks, _ := releaseBlocks(cl, splitLines(src))
+	return assemble(head, blocks, foot), nil
+}
@@ -5,7 +5,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/WagnerMatos/semver/internal/clock"
 	"github.com/WagnerMatos/semver/internal/version"
 )
 
@@ -14,25 +16,23 @@ func TestFileService_Update(t *testing.T) {
 	changelogFile := filepath.Join(tempDir, "CHANGELOG.md")
 
 	tests := []struct {
-		name      string
-		version   version.Version
-		vType     version.Type
-		shortDesc string
-		longDesc  string
-		wantErr   bool
-		check     func(t *testing.T, content string)
+		name    string
+		version version.Version
+		vType   version.Type
+		entries []Entry
+		wantErr bool
+		check   func(t *testing.T, content string)
 	}{
 		{
-			name:      "valid update with short description",
-			version:   version.Version{Major: 1, Minor: 2, Patch: 3},
-			vType:     version.Major,
-			shortDesc: "test commit",
-			longDesc:  "",
-			wantErr:   false,
+			name:    "valid update with short description",
+			version: version.Version{Major: 1, Minor: 2, Patch: 3},
+			vType:   version.Major,
+			entries: []Entry{{Short: "test commit"}},
+			wantErr: false,
 			check: func(t *testing.T, content string) {
 				expected := []string{
 					"[1.2.3]",
-					"Major",
+					"### Changed",
 					"test commit",
 				}
 				for _, exp := range expected {
@@ -43,16 +43,15 @@ func TestFileService_Update(t *testing.T) {
 			},
 		},
 		{
-			name:      "valid update with long description",
-			version:   version.Version{Major: 1, Minor: 2, Patch: 3},
-			vType:     version.Minor,
-			shortDesc: "test commit",
-			longDesc:  "long description",
-			wantErr:   false,
+			name:    "valid update with long description",
+			version: version.Version{Major: 1, Minor: 2, Patch: 3},
+			vType:   version.Minor,
+			entries: []Entry{{Short: "test commit", Long: "long description"}},
+			wantErr: false,
 			check: func(t *testing.T, content string) {
 				expected := []string{
 					"[1.2.3]",
-					"Minor",
+					"### Added",
 					"test commit",
 					"long description",
 				}
@@ -68,7 +67,7 @@ func TestFileService_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := New(changelogFile)
-			err := s.Update(tt.version, tt.vType, tt.shortDesc, tt.longDesc)
+			err := s.Update(tt.version, tt.vType, tt.entries, "", nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -84,3 +83,267 @@ func TestFileService_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestFileService_UpdateOrderAndLinks(t *testing.T) {
+	links, err := NewLinks("https://github.com/org/repo", GitHub, LinkTemplates{})
+	if err != nil {
+		t.Fatalf("NewLinks() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		initial string
+		opts    []Option
+		want    []string
+	}{
+		{
+			name: "newest first goes below unreleased",
+			initial: `# Changelog
+
+## [Unreleased]
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+
+[Unreleased]: https://github.com/org/repo/compare/v1.0.0...HEAD
+[1.0.0]: https://github.com/org/repo/releases/tag/v1.0.0
+`,
+			opts: []Option{WithLinks(links)},
+			want: []string{
+				"## [Unreleased]",
+				"## [1.1.0] - ",
+				"## [1.0.0] - 2024-12-23",
+				"[Unreleased]: https://github.com/org/repo/compare/v1.1.0...HEAD",
+				"[1.1.0]: https://github.com/org/repo/compare/v1.0.0...v1.1.0",
+				"[1.0.0]: https://github.com/org/repo/releases/tag/v1.0.0",
+			},
+		},
+		{
+			name: "oldest first is appended",
+			initial: `## [0.9.0] - 2024-12-22
+### Minor
+- Older
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+`,
+			want: []string{
+				"## [0.9.0] - 2024-12-22",
+				"## [1.0.0] - 2024-12-23",
+				"## [1.1.0] - ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if err := os.WriteFile(changelogFile, []byte(tt.initial), 0644); err != nil {
+				t.Fatalf("Failed to write changelog: %v", err)
+			}
+
+			s := New(changelogFile, tt.opts...)
+			if err := s.Update(version.Version{Major: 1, Minor: 1, Patch: 0}, version.Minor, []Entry{{Short: "feature"}}, "", nil); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			content, err := os.ReadFile(changelogFile)
+			if err != nil {
+				t.Fatalf("Failed to read changelog file: %v", err)
+			}
+
+			rest := string(content)
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("Update() content missing %q in order:\n%s", want, content)
+				}
+				rest = rest[i+len(want):]
+			}
+		})
+	}
+}
+
+func TestFileService_UpdateMultipleEntries(t *testing.T) {
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	entries := []Entry{
+		{Category: Fixed, Short: "fix one"},
+		{Category: Added, Short: "feature"},
+		{Category: "Performance", Short: "faster"},
+		{Category: Fixed, Short: "fix two", Long: "details"},
+		{Short: "uncategorised"},
+	}
+
+	s := New(changelogFile)
+	if err := s.Update(version.Version{Major: 1, Minor: 1, Patch: 0}, version.Patch, entries, "", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Failed to read changelog file: %v", err)
+	}
+
+	want := `### Added
+- feature
+### Fixed
+- fix one
+- fix two
+
+  details
+- uncategorised
+### Performance
+- faster
+`
+	if !strings.HasSuffix(string(content), want) {
+		t.Errorf("Update() content =\n%s\nwant suffix\n%s", content, want)
+	}
+
+	if n := strings.Count(string(content), "## [1.1.0]"); n != 1 {
+		t.Errorf("release heading written %d times, want 1", n)
+	}
+}
+
+func TestFileService_UpdateMigration(t *testing.T) {
+	dir := t.TempDir()
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	upgradingFile := filepath.Join(dir, "UPGRADING.md")
+
+	s := New(changelogFile, WithUpgrading(upgradingFile))
+	releases := []struct {
+		version   version.Version
+		vType     version.Type
+		migration string
+	}{
+		{version.Version{Major: 1}, version.Major, "Rename `config.yml` to `.semver.json`.\n\n- Run `semver lint --fix`."},
+		{version.Version{Major: 1, Minor: 1}, version.Minor, ""},
+		{version.Version{Major: 2}, version.Major, "Go 1.23 is required."},
+	}
+	for _, r := range releases {
+		if err := s.Update(r.version, r.vType, []Entry{{Short: "change"}}, r.migration, nil); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	cl, err := ParseFile(changelogFile)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	r := cl.Find("1.0.0")
+	last := r.Sections[len(r.Sections)-1]
+	if last.Name != string(Migration) || last.Text != releases[0].migration || len(last.Entries) != 0 {
+		t.Errorf("migration section = %+v", last)
+	}
+	if issues := Lint(cl, LintOptions{}); len(issues) != 0 {
+		t.Errorf("Lint() = %v, want no issues", issues)
+	}
+
+	content, err := os.ReadFile(upgradingFile)
+	if err != nil {
+		t.Fatalf("Failed to read upgrade guide: %v", err)
+	}
+	want := `# Upgrading
+
+## Upgrading to 2.0.0
+
+Go 1.23 is required.
+
+## Upgrading to 1.0.0
+
+Rename ` + "`config.yml` to `.semver.json`." + `
+
+- Run ` + "`semver lint --fix`." + `
+`
+	if string(content) != want {
+		t.Errorf("upgrade guide =\n%s\nwant:\n%s", content, want)
+	}
+}
+
+func TestFileService_UpdateContributors(t *testing.T) {
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	contributors := []Contributor{{Name: "Alice Doe"}, {Name: "Carol", FirstTime: true}}
+
+	s := New(changelogFile, WithClock(clock.Fixed(time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC))))
+	if err := s.Update(version.Version{Major: 2}, version.Major, []Entry{{Short: "change"}}, "Go 1.23 is required.", contributors); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Failed to read changelog file: %v", err)
+	}
+	want := `## [2.0.0] - 2024-12-24
+### Changed
+- change
+### Migration
+Go 1.23 is required.
+### Contributors
+- Alice Doe
+- Carol (first contribution)
+`
+	if !strings.HasSuffix(string(content), want) {
+		t.Fatalf("Update() content =\n%s\nwant suffix\n%s", content, want)
+	}
+
+	cl, err := ParseFile(changelogFile)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	sections := cl.Find("2.0.0").Sections
+	if last := sections[len(sections)-1]; last.Name != string(Contributors) || last.Text != "- Alice Doe\n- Carol (first contribution)" || len(last.Entries) != 0 {
+		t.Errorf("contributors section = %+v", last)
+	}
+	if issues := Lint(cl, LintOptions{}); len(issues) != 0 {
+		t.Errorf("Lint() = %v, want no issues", issues)
+	}
+
+	var b strings.Builder
+	if err := Render(&b, cl); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasSuffix(b.String(), want) {
+		t.Errorf("Render() =\n%s\nwant suffix\n%s", b.String(), want)
+	}
+}
+
+func TestFileService_UpdateClock(t *testing.T) {
+	// 2025-01-01 08:00 in UTC+14 is still 2024-12-31 in UTC.
+	at := clock.Fixed(time.Date(2025, 1, 1, 8, 0, 0, 0, time.FixedZone("UTC+14", 14*60*60)))
+
+	tests := []struct {
+		name   string
+		layout string
+		want   string
+	}{
+		{name: "default layout", want: "## [1.1.0] - 2024-12-31\n"},
+		{name: "custom layout", layout: "January 2, 2006", want: "## [1.1.0] - December 31, 2024\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outputs []string
+			for range 2 {
+				changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+				s := New(changelogFile, WithClock(at), WithDateLayout(tt.layout))
+				if err := s.Update(version.Version{Major: 1, Minor: 1}, version.Minor, []Entry{{Short: "feature"}}, "", nil); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				content, err := os.ReadFile(changelogFile)
+				if err != nil {
+					t.Fatalf("Failed to read changelog: %v", err)
+				}
+				outputs = append(outputs, string(content))
+			}
+
+			if !strings.Contains(outputs[0], tt.want) {
+				t.Errorf("Update() content =\n%s\nwant heading %q", outputs[0], tt.want)
+			}
+			if outputs[0] != outputs[1] {
+				t.Errorf("Update() is not reproducible:\n%s\n---\n%s", outputs[0], outputs[1])
+			}
+		})
+	}
+}
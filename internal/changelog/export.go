@@ -0,0 +1,378 @@
+package changelog
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/WagnerMatos/semver/internal/clock"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatJSON Format = "json"
+	FormatAtom Format = "atom"
+	FormatRSS  Format = "rss"
+)
+
+// SchemaVersion is bumped whenever the JSON export changes incompatibly.
+const SchemaVersion = 1
+
+// DefaultDateLayout is the ISO 8601 date used in release headings.
+const DefaultDateLayout = "2006-01-02"
+
+type ExportOptions struct {
+	Title string
+	// URL is where the exported changelog is published. It is used for feed
+	// links and identifiers.
+	URL string
+	// DateLayout is the layout of release dates. Defaults to
+	// DefaultDateLayout.
+	DateLayout string
+	// Modified dates the releases in feeds whose date is missing or doesn't
+	// parse, usually the changelog file's modification time. When it is
+	// zero, RSS leaves them undated and Atom, which requires a date, uses
+	// the newest release date or else Now.
+	Modified time.Time
+	// Now defaults to the current time.
+	Now time.Time
+}
+
+func Export(w io.Writer, cl *Changelog, format Format, opts ExportOptions) error {
+	if opts.Title == "" {
+		opts.Title = cl.Title
+	}
+	if opts.Title == "" {
+		opts.Title = "Changelog"
+	}
+	if opts.DateLayout == "" {
+		opts.DateLayout = DefaultDateLayout
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Wall.Now()
+	}
+
+	switch format {
+	case FormatHTML:
+		return exportHTML(w, cl, opts)
+	case FormatJSON:
+		return exportJSON(w, cl)
+	case FormatAtom:
+		return exportAtom(w, cl, opts)
+	case FormatRSS:
+		return exportRSS(w, cl, opts)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// Anchor returns the HTML fragment identifier used for a release.
+func Anchor(ver string) string {
+	if ver == Unreleased {
+		return "unreleased"
+	}
+	return "v" + ver
+}
+
+type jsonChangelog struct {
+	Schema   int           `json:"schema"`
+	Title    string        `json:"title"`
+	Releases []jsonRelease `json:"releases"`
+}
+
+type jsonRelease struct {
+	Version    string        `json:"version"`
+	Date       string        `json:"date"`
+	Yanked     bool          `json:"yanked"`
+	YankReason string        `json:"yankReason,omitempty"`
+	Sections   []jsonSection `json:"sections"`
+}
+
+type jsonSection struct {
+	Name    string      `json:"name"`
+	Text    string      `json:"text,omitempty"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+type jsonEntry struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
+
+func exportJSON(w io.Writer, cl *Changelog) error {
+	out := jsonChangelog{
+		Schema:   SchemaVersion,
+		Title:    cl.Title,
+		Releases: make([]jsonRelease, 0, len(cl.Releases)),
+	}
+	for _, r := range cl.Releases {
+		jr := jsonRelease{
+			Version:    r.Version,
+			Date:       r.Date,
+			Yanked:     r.Yanked,
+			YankReason: r.YankReason,
+			Sections:   make([]jsonSection, 0, len(r.Sections)),
+		}
+		for _, s := range r.Sections {
+			js := jsonSection{Name: s.Name, Text: s.Text, Entries: make([]jsonEntry, 0, len(s.Entries))}
+			for _, e := range s.Entries {
+				js.Entries = append(js.Entries, jsonEntry{Summary: e.Short, Description: e.Long})
+			}
+			jr.Sections = append(jr.Sections, js)
+		}
+		out.Releases = append(out.Releases, jr)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encoding json: %w", err)
+	}
+	return nil
+}
+
+var htmlFuncs = template.FuncMap{
+	"anchor":   Anchor,
+	"markdown": markdownHTML,
+	"inline":   func(s string) template.HTML { return template.HTML(inlineHTML(s)) },
+}
+
+var releaseHTML = template.Must(template.New("release").Funcs(htmlFuncs).Parse(
+	`{{range .Sections}}{{if .Name}}<h3>{{.Name}}</h3>
+{{end}}{{with .Text}}<pre>{{.}}</pre>
+{{end}}{{if .Entries}}<ul>
+{{range .Entries}}<li>{{inline .Short}}{{with .Long}}
+{{markdown .}}
+{{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}`))
+
+var pageHTML = template.Must(template.Must(releaseHTML.Clone()).New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Releases}}<section id="{{anchor .Version}}">
+<h2><a href="#{{anchor .Version}}">{{.Version}}</a>{{if .Date}} - <time datetime="{{.Date}}">{{.Date}}</time>{{end}}{{if .Yanked}} <strong>[YANKED]</strong>{{with .YankReason}} {{.}}{{end}}{{end}}</h2>
+{{template "release" .}}</section>
+{{end}}</body>
+</html>
+`))
+
+func exportHTML(w io.Writer, cl *Changelog, opts ExportOptions) error {
+	data := struct {
+		Title    string
+		Releases []*Release
+	}{opts.Title, cl.Releases}
+
+	if err := pageHTML.Execute(w, data); err != nil {
+		return fmt.Errorf("rendering html: %w", err)
+	}
+	return nil
+}
+
+func releaseContent(r *Release) (string, error) {
+	var b strings.Builder
+	if err := releaseHTML.Execute(&b, r); err != nil {
+		return "", fmt.Errorf("rendering release %s: %w", r.Version, err)
+	}
+	return b.String(), nil
+}
+
+// feedReleases returns the releases that belong in a feed: everything except
+// the Unreleased section, paired with its parsed date or, failing that, the
+// modification time.
+func feedReleases(cl *Changelog, opts ExportOptions) ([]*Release, []time.Time) {
+	var (
+		releases []*Release
+		dates    []time.Time
+	)
+	for _, r := range cl.Releases {
+		if r.Version == Unreleased {
+			continue
+		}
+		d, err := time.Parse(opts.DateLayout, r.Date)
+		if err != nil {
+			d = opts.Modified
+		}
+		releases = append(releases, r)
+		dates = append(dates, d)
+	}
+	return releases, dates
+}
+
+func releaseLink(opts ExportOptions, ver string) string {
+	if opts.URL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(opts.URL, "#") + "#" + Anchor(ver)
+}
+
+func releaseID(opts ExportOptions, ver string) string {
+	if link := releaseLink(opts, ver); link != "" {
+		return link
+	}
+	return "urn:semver:release:" + ver
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func exportAtom(w io.Writer, cl *Changelog, opts ExportOptions) error {
+	releases, dates := feedReleases(cl, opts)
+
+	feed := atomFeed{
+		Title: opts.Title,
+		ID:    opts.URL,
+	}
+	if feed.ID == "" {
+		feed.ID = "urn:semver:changelog"
+	} else {
+		feed.Link = &atomLink{Href: opts.URL}
+	}
+
+	// Atom requires every entry and the feed to be dated.
+	var latest time.Time
+	for _, d := range dates {
+		if d.After(latest) {
+			latest = d
+		}
+	}
+	if latest.IsZero() {
+		latest = opts.Now
+	}
+
+	for i, r := range releases {
+		content, err := releaseContent(r)
+		if err != nil {
+			return err
+		}
+
+		updated := dates[i]
+		if updated.IsZero() {
+			updated = latest
+		}
+		entry := atomEntry{
+			Title:   r.Version,
+			ID:      releaseID(opts, r.Version),
+			Updated: updated.Format(time.RFC3339),
+			Content: atomContent{Type: "html", Body: content},
+		}
+		if link := releaseLink(opts, r.Version); link != "" {
+			entry.Link = &atomLink{Href: link}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = latest.Format(time.RFC3339)
+
+	return writeXML(w, feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func exportRSS(w io.Writer, cl *Changelog, opts ExportOptions) error {
+	releases, dates := feedReleases(cl, opts)
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       opts.Title,
+			Link:        opts.URL,
+			Description: opts.Title,
+		},
+	}
+
+	for i, r := range releases {
+		content, err := releaseContent(r)
+		if err != nil {
+			return err
+		}
+
+		item := rssItem{
+			Title:       r.Version,
+			Link:        releaseLink(opts, r.Version),
+			GUID:        rssGUID{IsPermaLink: opts.URL != "", Value: releaseID(opts, r.Version)},
+			Description: content,
+		}
+		if !dates[i].IsZero() {
+			item.PubDate = dates[i].Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return writeXML(w, feed)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing feed: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding feed: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing feed: %w", err)
+	}
+	return nil
+}
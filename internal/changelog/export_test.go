@@ -0,0 +1,214 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const exportInput = `# Changelog
+
+## [Unreleased]
+### Added
+- Pending
+
+## [1.1.0] - 2024-12-24
+### Fixed
+- **core:** fix <loader> ([#12](https://example.com/issues/12))
+  Details
+
+  - run ` + "`semver lint`" + `
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+`
+
+func TestExport(t *testing.T) {
+	cl, err := Parse(strings.NewReader(exportInput))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		opts    ExportOptions
+		wantErr error
+		check   func(t *testing.T, out []byte)
+	}{
+		{
+			name:   "html",
+			format: FormatHTML,
+			check: func(t *testing.T, out []byte) {
+				expected := []string{
+					"<!DOCTYPE html>",
+					`<section id="v1.1.0">`,
+					`<a href="#v1.1.0">1.1.0</a>`,
+					`<section id="unreleased">`,
+					`<li><strong>core:</strong> fix &lt;loader&gt; (<a href="https://example.com/issues/12">#12</a>)`,
+					"<p>Details</p>",
+					"<ul>\n<li>run <code>semver lint</code></li>\n</ul>",
+				}
+				for _, exp := range expected {
+					if !bytes.Contains(out, []byte(exp)) {
+						t.Errorf("Export() html missing %q", exp)
+					}
+				}
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			check: func(t *testing.T, out []byte) {
+				var got jsonChangelog
+				if err := json.Unmarshal(out, &got); err != nil {
+					t.Fatalf("invalid json: %v", err)
+				}
+				if got.Schema != SchemaVersion || len(got.Releases) != 3 {
+					t.Fatalf("got schema %d with %d releases", got.Schema, len(got.Releases))
+				}
+				e := got.Releases[1].Sections[0].Entries[0]
+				if e.Summary != "**core:** fix <loader> ([#12](https://example.com/issues/12))" || e.Description != "Details\n\n- run `semver lint`" {
+					t.Errorf("entry = %+v", e)
+				}
+			},
+		},
+		{
+			name:   "atom",
+			format: FormatAtom,
+			opts:   ExportOptions{URL: "https://example.com/changelog"},
+			check: func(t *testing.T, out []byte) {
+				var got atomFeed
+				if err := xml.Unmarshal(out, &got); err != nil {
+					t.Fatalf("invalid xml: %v", err)
+				}
+				if len(got.Entries) != 2 {
+					t.Fatalf("len(Entries) = %d, want 2 (Unreleased excluded)", len(got.Entries))
+				}
+				if got.Updated != "2024-12-24T00:00:00Z" {
+					t.Errorf("Updated = %q", got.Updated)
+				}
+				if got.Entries[0].Updated != "2024-12-24T00:00:00Z" {
+					t.Errorf("entry Updated = %q", got.Entries[0].Updated)
+				}
+				if got.Entries[0].ID != "https://example.com/changelog#v1.1.0" {
+					t.Errorf("entry ID = %q", got.Entries[0].ID)
+				}
+			},
+		},
+		{
+			name:   "rss",
+			format: FormatRSS,
+			check: func(t *testing.T, out []byte) {
+				var got rssFeed
+				if err := xml.Unmarshal(out, &got); err != nil {
+					t.Fatalf("invalid xml: %v", err)
+				}
+				if len(got.Channel.Items) != 2 {
+					t.Fatalf("len(Items) = %d, want 2", len(got.Channel.Items))
+				}
+				if got.Channel.Items[1].GUID.Value != "urn:semver:release:1.0.0" {
+					t.Errorf("GUID = %q", got.Channel.Items[1].GUID.Value)
+				}
+			},
+		},
+		{
+			name:    "unknown format",
+			format:  "pdf",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Export(&buf, cl, tt.format, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Export() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestExport_UndatedRelease(t *testing.T) {
+	const (
+		undated = "# Changelog\n\n## [1.1.0] - someday\n### Added\n- Export\n"
+		older   = "\n## [1.0.0] - 2024-12-20\n### Added\n- Import\n"
+	)
+	modified := time.Date(2024, 12, 25, 9, 30, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		modified time.Time
+		// wantUpdated is the date of the undated release in Atom and
+		// wantFeed that of the feed.
+		wantUpdated string
+		wantFeed    string
+		wantPubDate string
+	}{
+		{
+			name:        "modification time",
+			input:       undated + older,
+			modified:    modified,
+			wantUpdated: "2024-12-25T09:30:00Z",
+			wantFeed:    "2024-12-25T09:30:00Z",
+			wantPubDate: "Wed, 25 Dec 2024 09:30:00 +0000",
+		},
+		{
+			name:        "newest release date",
+			input:       undated + older,
+			wantUpdated: "2024-12-20T00:00:00Z",
+			wantFeed:    "2024-12-20T00:00:00Z",
+		},
+		{
+			name:        "current time",
+			input:       undated,
+			wantUpdated: "2025-01-02T08:00:00Z",
+			wantFeed:    "2025-01-02T08:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			opts := ExportOptions{Modified: tt.modified, Now: now}
+
+			var atom bytes.Buffer
+			if err := Export(&atom, cl, FormatAtom, opts); err != nil {
+				t.Fatalf("Export() atom error = %v", err)
+			}
+			var feed atomFeed
+			if err := xml.Unmarshal(atom.Bytes(), &feed); err != nil {
+				t.Fatalf("invalid xml: %v", err)
+			}
+			if feed.Updated != tt.wantFeed || feed.Entries[0].Updated != tt.wantUpdated {
+				t.Errorf("Updated = %q, entry Updated = %q, want %q and %q", feed.Updated, feed.Entries[0].Updated, tt.wantFeed, tt.wantUpdated)
+			}
+
+			var rss bytes.Buffer
+			if err := Export(&rss, cl, FormatRSS, opts); err != nil {
+				t.Fatalf("Export() rss error = %v", err)
+			}
+			var channel rssFeed
+			if err := xml.Unmarshal(rss.Bytes(), &channel); err != nil {
+				t.Fatalf("invalid xml: %v", err)
+			}
+			if got := channel.Channel.Items[0].PubDate; got != tt.wantPubDate {
+				t.Errorf("PubDate = %q, want %q", got, tt.wantPubDate)
+			}
+		})
+	}
+}
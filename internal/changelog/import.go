@@ -0,0 +1,386 @@
+package changelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrUnknownImportFormat = errors.New("unknown import format")
+
+type ImportFormat string
+
+const (
+	// ImportConventional reads the markdown written by conventional-changelog
+	// and standard-version.
+	ImportConventional ImportFormat = "conventional"
+	// ImportGitHub reads GitHub Releases as exported by the REST API
+	// (GET /repos/{owner}/{repo}/releases).
+	ImportGitHub ImportFormat = "github"
+	// ImportNews reads free-form NEWS and HISTORY files.
+	ImportNews ImportFormat = "news"
+)
+
+// Import converts a changelog from another tool. Anything that could not be
+// mapped onto a release or category is returned as an issue; the line refers
+// to the input, or to the release's position in a GitHub export.
+func Import(r io.Reader, format ImportFormat) (*Changelog, []Issue, error) {
+	imp := &importer{cl: &Changelog{Title: "Changelog"}}
+
+	var err error
+	switch format {
+	case ImportConventional:
+		err = imp.conventional(r)
+	case ImportGitHub:
+		err = imp.github(r)
+	case ImportNews:
+		err = imp.news(r)
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownImportFormat, format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return imp.cl, imp.issues, nil
+}
+
+// sectionSynonyms maps headings used by other tools onto categories.
+var sectionSynonyms = map[string]Category{
+	"features":                 Added,
+	"new features":             Added,
+	"feature":                  Added,
+	"enhancements":             Added,
+	"bug fixes":                Fixed,
+	"bugfixes":                 Fixed,
+	"fixes":                    Fixed,
+	"performance improvements": Changed,
+	"performance":              Changed,
+	"code refactoring":         Changed,
+	"documentation":            Changed,
+	"what's changed":           Changed,
+	"changes":                  Changed,
+	"other changes":            Changed,
+	"breaking changes":         Changed,
+	"reverts":                  Removed,
+	"deprecations":             Deprecated,
+	"security fixes":           Security,
+}
+
+func importCategory(name string) (Category, bool) {
+	name = strings.Trim(strings.TrimSpace(name), "*:_ ")
+	if c, ok := LookupCategory(name); ok {
+		return c, true
+	}
+	c, ok := sectionSynonyms[strings.ToLower(name)]
+	return c, ok
+}
+
+// verbCategories guesses a category for free-form entries from their first
+// word.
+var verbCategories = []struct {
+	prefix   string
+	category Category
+}{
+	{"add", Added},
+	{"new", Added},
+	{"support", Added},
+	{"fix", Fixed},
+	{"remove", Removed},
+	{"drop", Removed},
+	{"deprecate", Deprecated},
+	{"security", Security},
+}
+
+func guessCategory(text string) Category {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	for _, v := range verbCategories {
+		if strings.HasPrefix(lower, v.prefix) {
+			return v.category
+		}
+	}
+	return Changed
+}
+
+var DefaultDateLayouts = []string{
+	DefaultDateLayout,
+	"2006/01/02",
+	"2006.01.02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"02 Jan 2006",
+	time.RFC3339,
+}
+
+// normalizeDate converts a date in one of the common layouts to YYYY-MM-DD.
+func normalizeDate(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range DefaultDateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d.Format(DefaultDateLayout), true
+		}
+	}
+	return "", false
+}
+
+type importer struct {
+	cl      *Changelog
+	release *Release
+	section *Section
+	entry   *Entry
+	issues  []Issue
+	// breaking is set while in a BREAKING CHANGES section, whose entries
+	// are marked rather than given their own category.
+	breaking bool
+	// skipping is set while in a section that holds no changes.
+	skipping bool
+	// gap is set after a blank line within the current entry.
+	gap bool
+}
+
+func (imp *importer) report(line int, format string, args ...any) {
+	imp.issues = append(imp.issues, Issue{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (imp *importer) startRelease(line int, ver, date string) {
+	imp.release = &Release{Version: ver, Line: line}
+	if date != "" {
+		if d, ok := normalizeDate(date); ok {
+			imp.release.Date = d
+		} else {
+			imp.report(line, "unrecognised date %q for %s", date, ver)
+		}
+	}
+	imp.cl.Releases = append(imp.cl.Releases, imp.release)
+	imp.section = nil
+	imp.entry = nil
+	imp.breaking = false
+	imp.skipping = false
+}
+
+func (imp *importer) startSection(line int, name string) {
+	imp.entry = nil
+	imp.skipping = false
+	imp.breaking = strings.EqualFold(strings.Trim(name, "*:_ "), "breaking changes")
+	c, ok := importCategory(name)
+	if !ok {
+		imp.report(line, "unknown section %q, entries filed under %s", name, Changed)
+		c = Changed
+	}
+	imp.section = &Section{Name: string(c), Line: line}
+	imp.release.Sections = append(imp.release.Sections, imp.section)
+}
+
+// addEntry adds an entry to the current section, or to a section guessed
+// from the text when there is none.
+func (imp *importer) addEntry(line int, text string) {
+	text = strings.TrimSpace(text)
+	c := guessCategory(text)
+	if imp.section != nil {
+		c = Category(imp.section.Name)
+	}
+	if imp.breaking {
+		text = "**BREAKING:** " + text
+	}
+
+	sec := imp.section
+	if sec == nil {
+		sec = &Section{Name: string(c), Line: line}
+		imp.release.Sections = append(imp.release.Sections, sec)
+	}
+	imp.entry = &Entry{Category: c, Short: text, Line: line}
+	imp.gap = false
+	sec.Entries = append(sec.Entries, imp.entry)
+}
+
+// continueEntry adds an indented line to the current entry. Lines directly
+// below the bullet continue its text; anything after a blank line is the
+// long description.
+func (imp *importer) continueEntry(text string) {
+	switch {
+	case !imp.gap && imp.entry.Long == "":
+		imp.entry.Short += " " + text
+	case imp.gap:
+		imp.entry.Long = strings.TrimSpace(imp.entry.Long + "\n\n" + text)
+	default:
+		imp.entry.Long += "\n" + text
+	}
+	imp.gap = false
+}
+
+func (imp *importer) blank() {
+	if imp.entry != nil {
+		imp.gap = true
+	}
+}
+
+var (
+	ccHeading   = regexp.MustCompile(`^#{1,3}\s+\[?v?(\d+\.\d+\.\d+[0-9A-Za-z.+-]*)\]?(?:\([^)]*\))?\s*(?:\((\d{4}-\d{2}-\d{2})\))?`)
+	shaLink     = regexp.MustCompile(`\s*\(\[[0-9a-f]{7,40}\]\([^)]*\)\)`)
+	bulletLine  = regexp.MustCompile(`^(\s*)[*+-]\s+(.*)$`)
+	anchorLine  = regexp.MustCompile(`^<a name="[^"]*"></a>$`)
+	newsVersion = regexp.MustCompile(`^(?:#+\s*)?(?:(?i:version|release)\s+)?\[?v?(\d+\.\d+\.\d+[0-9A-Za-z.+-]*)\]?`)
+	newsDate    = regexp.MustCompile(`(\d{4}[-/.]\d{2}[-/.]\d{2}|[A-Z][a-z]+ \d{1,2}, \d{4}|\d{1,2} [A-Z][a-z]+ \d{4})`)
+	underline   = regexp.MustCompile(`^(=+|-+)$`)
+)
+
+func (imp *importer) conventional(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch m := ccHeading.FindStringSubmatch(line); {
+		case trimmed == "":
+			imp.blank()
+
+		case anchorLine.MatchString(trimmed):
+			continue
+
+		case m != nil:
+			imp.startRelease(lineNo, m[1], m[2])
+
+		case strings.HasPrefix(line, "# ") && imp.release == nil:
+			imp.cl.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+
+		case strings.HasPrefix(line, "#") && imp.release != nil:
+			imp.startSection(lineNo, strings.TrimLeft(line, "# "))
+
+		case imp.release == nil:
+			imp.report(lineNo, "text before the first release: %q", trimmed)
+
+		default:
+			if m := bulletLine.FindStringSubmatch(line); m != nil && m[1] == "" {
+				imp.addEntry(lineNo, shaLink.ReplaceAllString(m[2], ""))
+			} else if imp.entry != nil && strings.HasPrefix(line, " ") {
+				imp.continueEntry(trimmed)
+			} else {
+				imp.report(lineNo, "unmapped text: %q", trimmed)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (imp *importer) github(r io.Reader) error {
+	var releases []githubRelease
+	if err := json.NewDecoder(r).Decode(&releases); err != nil {
+		return fmt.Errorf("decoding github releases: %w", err)
+	}
+
+	for i, gr := range releases {
+		n := i + 1
+		if gr.Draft {
+			imp.report(n, "skipped draft release %q", gr.TagName)
+			continue
+		}
+
+		ver := strings.TrimPrefix(gr.TagName, "v")
+		if !semverPattern.MatchString(ver) {
+			imp.report(n, "tag %q is not a semantic version", gr.TagName)
+		}
+
+		date := gr.PublishedAt
+		if date == "" {
+			date = gr.CreatedAt
+		}
+		imp.startRelease(n, ver, date)
+
+		for _, line := range strings.Split(strings.ReplaceAll(gr.Body, "\r\n", "\n"), "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case trimmed == "":
+				imp.blank()
+			case strings.HasPrefix(trimmed, "#"):
+				name := strings.TrimLeft(trimmed, "# ")
+				if strings.EqualFold(name, "New Contributors") {
+					// Contributor shout-outs aren't changes.
+					imp.section = nil
+					imp.entry = nil
+					imp.skipping = true
+					continue
+				}
+				imp.startSection(n, name)
+			case imp.skipping:
+				continue
+			default:
+				if m := bulletLine.FindStringSubmatch(line); m != nil && m[1] == "" {
+					imp.addEntry(n, m[2])
+				} else if imp.entry != nil && strings.HasPrefix(line, " ") {
+					imp.continueEntry(trimmed)
+				} else {
+					imp.report(n, "unmapped text in %s: %q", gr.TagName, trimmed)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *importer) news(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	bulletIndent := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			imp.blank()
+			continue
+		}
+		if underline.MatchString(trimmed) {
+			continue
+		}
+
+		// Version lines start at the margin, so indented text that happens to
+		// begin with a version stays part of its entry.
+		if m := newsVersion.FindStringSubmatch(line); m != nil && !bulletLine.MatchString(line) {
+			date := strings.Trim(line[len(m[0]):], " -–—():")
+			if d := newsDate.FindString(date); d != "" {
+				date = d
+			}
+			imp.startRelease(lineNo, m[1], date)
+			bulletIndent = 0
+			continue
+		}
+
+		if imp.release == nil {
+			imp.report(lineNo, "text before the first release: %q", trimmed)
+			continue
+		}
+
+		if m := bulletLine.FindStringSubmatch(line); m != nil && (imp.entry == nil || len(m[1]) <= bulletIndent) {
+			bulletIndent = len(m[1])
+			imp.addEntry(lineNo, m[2])
+			continue
+		}
+
+		if imp.entry != nil && len(line)-len(strings.TrimLeft(line, " \t")) > bulletIndent {
+			imp.continueEntry(trimmed)
+			continue
+		}
+
+		imp.report(lineNo, "unmapped text: %q", trimmed)
+	}
+	return scanner.Err()
+}
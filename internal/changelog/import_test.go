@@ -0,0 +1,159 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ImportFormat
+		input  string
+		want   string
+		issues []string
+	}{
+		{
+			name:   "conventional-changelog",
+			format: ImportConventional,
+			input: `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+<a name="1.1.0"></a>
+## [1.1.0](https://github.com/o/r/compare/v1.0.0...v1.1.0) (2024-03-02)
+
+### Features
+
+* **api:** add pagination ([abc1234](https://github.com/o/r/commit/abc1234))
+
+### BREAKING CHANGES
+
+* the list endpoint returns pages
+
+### Miscellaneous
+
+* tidy up
+
+# 1.0.0 (2024-01-15)
+
+### Bug Fixes
+
+* handle empty input
+`,
+			want: `# Changelog
+
+## [1.1.0] - 2024-03-02
+### Added
+- **api:** add pagination
+### Changed
+- **BREAKING:** the list endpoint returns pages
+- tidy up
+
+## [1.0.0] - 2024-01-15
+### Fixed
+- handle empty input
+`,
+			issues: []string{
+				`3: text before the first release: "All notable changes to this project will be documented in this file."`,
+				`16: unknown section "Miscellaneous", entries filed under Changed`,
+			},
+		},
+		{
+			name:   "github releases",
+			format: ImportGitHub,
+			input: `[
+  {"tag_name": "v2.0.0", "draft": true, "body": "wip"},
+  {"tag_name": "v1.1.0", "published_at": "2024-03-02T10:00:00Z",
+   "body": "## What's Changed\r\n* Faster startup by @alice in #12\r\n\r\n## New Contributors\r\n* @alice made their first contribution"},
+  {"tag_name": "v1.0.0", "published_at": "2024-01-15T09:00:00Z",
+   "body": "### Bug Fixes\n- Handle empty input\n  when reading stdin\n\n  Reported in the forum.\n\nThanks everyone!"}
+]`,
+			want: `# Changelog
+
+## [1.1.0] - 2024-03-02
+### Changed
+- Faster startup by @alice in #12
+
+## [1.0.0] - 2024-01-15
+### Fixed
+- Handle empty input when reading stdin
+
+  Reported in the forum.
+`,
+			issues: []string{
+				`1: skipped draft release "v2.0.0"`,
+				`3: unmapped text in v1.0.0: "Thanks everyone!"`,
+			},
+		},
+		{
+			name:   "news file",
+			format: ImportNews,
+			input: `Version 1.1.0 (March 2, 2024)
+=============================
+
+* Added a --quiet flag.
+* Fixed crash on empty input,
+  reported by several users.
+* Improved error messages.
+
+1.0.0 - 2024/01/15
+------------------
+
+* First release.
+
+0.9.0 - someday
+`,
+			want: `# Changelog
+
+## [1.1.0] - 2024-03-02
+### Added
+- Added a --quiet flag.
+### Changed
+- Improved error messages.
+### Fixed
+- Fixed crash on empty input, reported by several users.
+
+## [1.0.0] - 2024-01-15
+### Changed
+- First release.
+
+## [0.9.0]
+`,
+			issues: []string{
+				`14: unrecognised date "someday" for 0.9.0`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, issues, err := Import(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			var b strings.Builder
+			if err := Render(&b, cl); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Import() =\n%s\nwant:\n%s", b.String(), tt.want)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, fmt.Sprintf("%d: %s", issue.Line, issue.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.issues, "\n") {
+				t.Errorf("issues =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.issues, "\n"))
+			}
+		})
+	}
+
+	if _, _, err := Import(strings.NewReader(""), "keepachangelog"); !errors.Is(err, ErrUnknownImportFormat) {
+		t.Errorf("Import() error = %v, want %v", err, ErrUnknownImportFormat)
+	}
+}
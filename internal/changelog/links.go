@@ -0,0 +1,267 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var (
+	ErrUnknownHost   = errors.New("unknown repository host")
+	ErrInvalidRemote = errors.New("invalid remote url")
+)
+
+type Host string
+
+const (
+	GitHub    Host = "github"
+	GitLab    Host = "gitlab"
+	Bitbucket Host = "bitbucket"
+	Gitea     Host = "gitea"
+)
+
+// LinkTemplates build the footer references. Compare is used for every
+// release that has a predecessor and for Unreleased, Tag for the first
+// release. Both receive a linkData. Issue links issue and pull request
+// numbers in entries and receives a refData.
+type LinkTemplates struct {
+	Compare string
+	Tag     string
+	Issue   string
+}
+
+var hostTemplates = map[Host]LinkTemplates{
+	GitHub: {
+		Compare: "{{.Repo}}/compare/{{.Previous}}...{{.Current}}",
+		Tag:     "{{.Repo}}/releases/tag/{{.Current}}",
+		Issue:   "{{.Repo}}/issues/{{.Number}}",
+	},
+	GitLab: {
+		Compare: "{{.Repo}}/-/compare/{{.Previous}}...{{.Current}}",
+		Tag:     "{{.Repo}}/-/tags/{{.Current}}",
+		Issue:   "{{.Repo}}/-/issues/{{.Number}}",
+	},
+	Bitbucket: {
+		Compare: "{{.Repo}}/branches/compare/{{.Current}}%0D{{.Previous}}",
+		Tag:     "{{.Repo}}/commits/tag/{{.Current}}",
+		Issue:   "{{.Repo}}/issues/{{.Number}}",
+	},
+	Gitea: {
+		Compare: "{{.Repo}}/compare/{{.Previous}}...{{.Current}}",
+		Tag:     "{{.Repo}}/src/tag/{{.Current}}",
+		Issue:   "{{.Repo}}/issues/{{.Number}}",
+	},
+}
+
+type linkData struct {
+	Repo     string
+	Previous string
+	Current  string
+}
+
+// Links generates the compare-link references at the bottom of the
+// changelog.
+type Links struct {
+	Repo    string
+	Compare *template.Template
+	Tag     *template.Template
+	Issue   *template.Template
+	// TagName maps a version to its git tag.
+	TagName func(string) string
+}
+
+// NewLinks builds Links for a repository. An empty host is detected from the
+// repository URL; custom templates override the host's built-in ones.
+func NewLinks(repo string, host Host, custom LinkTemplates) (*Links, error) {
+	repo = strings.TrimSuffix(repo, "/")
+	if host == "" {
+		host = DetectHost(repo)
+	}
+
+	tmpls, ok := hostTemplates[host]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHost, host)
+	}
+	if custom.Compare != "" {
+		tmpls.Compare = custom.Compare
+	}
+	if custom.Tag != "" {
+		tmpls.Tag = custom.Tag
+	}
+	if custom.Issue != "" {
+		tmpls.Issue = custom.Issue
+	}
+
+	compare, err := template.New("compare").Option("missingkey=error").Parse(tmpls.Compare)
+	if err != nil {
+		return nil, fmt.Errorf("parsing compare url template: %w", err)
+	}
+	tag, err := template.New("tag").Option("missingkey=error").Parse(tmpls.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tag url template: %w", err)
+	}
+	issue, err := template.New("issue").Option("missingkey=error").Parse(tmpls.Issue)
+	if err != nil {
+		return nil, fmt.Errorf("parsing issue url template: %w", err)
+	}
+
+	return &Links{
+		Repo:    repo,
+		Compare: compare,
+		Tag:     tag,
+		Issue:   issue,
+		TagName: func(v string) string { return "v" + v },
+	}, nil
+}
+
+// DetectHost guesses the hosting service from a repository URL. Self-hosted
+// instances that can't be recognised are assumed to be Gitea, whose URLs
+// match GitHub's.
+func DetectHost(repo string) Host {
+	u, err := url.Parse(repo)
+	if err != nil {
+		return GitHub
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.Contains(host, "github"):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "bitbucket"):
+		return Bitbucket
+	default:
+		return Gitea
+	}
+}
+
+// RepositoryURL converts a git remote URL into the repository's web URL. It
+// accepts HTTPS remotes as well as both SSH forms:
+//
+//	https://github.com/org/repo.git
+//	git@github.com:org/repo.git
+//	ssh://git@github.com:22/org/repo.git
+func RepositoryURL(remote string) (string, error) {
+	remote = strings.TrimSpace(remote)
+
+	var host, path string
+	if !strings.Contains(remote, "://") {
+		// scp-like syntax: [user@]host:path
+		at := strings.LastIndex(remote, "@")
+		hostPath := remote[at+1:]
+		var ok bool
+		host, path, ok = strings.Cut(hostPath, ":")
+		if !ok || host == "" || path == "" {
+			return "", fmt.Errorf("%w: %s", ErrInvalidRemote, remote)
+		}
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("%w: %s", ErrInvalidRemote, remote)
+		}
+		host = u.Hostname()
+		if u.Scheme == "http" || u.Scheme == "https" {
+			host = u.Host
+		}
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRemote, remote)
+	}
+
+	return "https://" + host + "/" + path, nil
+}
+
+func (l *Links) render(t *template.Template, previous, current string) (string, error) {
+	var b strings.Builder
+	data := linkData{Repo: l.Repo, Previous: previous, Current: current}
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering link: %w", err)
+	}
+	return b.String(), nil
+}
+
+// references returns the link definitions for every release in cl, in the
+// order the releases appear.
+func (l *Links) references(cl *Changelog) ([]string, error) {
+	_, versions := releaseVersions(cl)
+	sorted := append([]*version.Version{}, versions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Compare(sorted[j]) < 0
+	})
+
+	previous := func(v *version.Version) *version.Version {
+		var prev *version.Version
+		for _, s := range sorted {
+			if s.Compare(v) >= 0 {
+				break
+			}
+			prev = s
+		}
+		return prev
+	}
+
+	var refs []string
+	done := make(map[string]bool)
+	for _, r := range cl.Releases {
+		if done[r.Version] {
+			continue
+		}
+
+		var (
+			link string
+			err  error
+		)
+		if r.Version == Unreleased {
+			if len(sorted) == 0 {
+				continue
+			}
+			latest := sorted[len(sorted)-1].String()
+			link, err = l.render(l.Compare, l.TagName(latest), "HEAD")
+		} else {
+			v, perr := version.ParseVersion(r.Version)
+			if perr != nil || !semverPattern.MatchString(r.Version) {
+				continue
+			}
+			if prev := previous(v); prev != nil {
+				link, err = l.render(l.Compare, l.TagName(prev.String()), l.TagName(r.Version))
+			} else {
+				link, err = l.render(l.Tag, "", l.TagName(r.Version))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		done[r.Version] = true
+		refs = append(refs, fmt.Sprintf("[%s]: %s", r.Version, link))
+	}
+	return refs, nil
+}
+
+// footer rebuilds the trailing link definitions: references to releases are
+// regenerated and any other definitions are kept after them.
+func (l *Links) footer(cl *Changelog) ([]string, error) {
+	refs, err := l.references(cl)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := make(map[string]bool)
+	for _, r := range cl.Releases {
+		managed[r.Version] = true
+	}
+	for _, link := range cl.Links {
+		if cl.footer > 0 && link.Line >= cl.footer && !managed[link.Name] {
+			refs = append(refs, fmt.Sprintf("[%s]: %s", link.Name, link.URL))
+		}
+	}
+	return refs, nil
+}
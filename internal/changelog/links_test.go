@@ -0,0 +1,142 @@
+package changelog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		want    string
+		wantErr error
+	}{
+		{"https", "https://github.com/org/repo.git", "https://github.com/org/repo", nil},
+		{"https without suffix", "https://gitlab.com/group/sub/repo", "https://gitlab.com/group/sub/repo", nil},
+		{"https with credentials and port", "https://user@git.example.com:3000/org/repo.git", "https://git.example.com:3000/org/repo", nil},
+		{"scp-like ssh", "git@github.com:org/repo.git", "https://github.com/org/repo", nil},
+		{"ssh url", "ssh://git@bitbucket.org:22/org/repo.git", "https://bitbucket.org/org/repo", nil},
+		{"no path", "git@github.com:", "", ErrInvalidRemote},
+		{"local path", "/srv/git/repo.git", "", ErrInvalidRemote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RepositoryURL(tt.remote)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RepositoryURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RepositoryURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectHost(t *testing.T) {
+	tests := []struct {
+		repo string
+		want Host
+	}{
+		{"https://github.com/org/repo", GitHub},
+		{"https://gitlab.example.com/org/repo", GitLab},
+		{"https://bitbucket.org/org/repo", Bitbucket},
+		{"https://git.example.com/org/repo", Gitea},
+	}
+
+	for _, tt := range tests {
+		if got := DetectHost(tt.repo); got != tt.want {
+			t.Errorf("DetectHost(%q) = %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestLinks_Footer(t *testing.T) {
+	input := `## [Unreleased]
+
+## [1.1.0] - 2024-12-24
+- b
+
+## [1.0.0] - 2024-12-23
+- a
+
+[Unreleased]: https://stale.example.com
+[keep a changelog]: https://keepachangelog.com
+`
+
+	tests := []struct {
+		name   string
+		host   Host
+		custom LinkTemplates
+		want   []string
+	}{
+		{
+			name: "github",
+			host: GitHub,
+			want: []string{
+				"[Unreleased]: https://example.com/org/repo/compare/v1.1.0...HEAD",
+				"[1.1.0]: https://example.com/org/repo/compare/v1.0.0...v1.1.0",
+				"[1.0.0]: https://example.com/org/repo/releases/tag/v1.0.0",
+				"[keep a changelog]: https://keepachangelog.com",
+			},
+		},
+		{
+			name: "gitlab",
+			host: GitLab,
+			want: []string{
+				"[Unreleased]: https://example.com/org/repo/-/compare/v1.1.0...HEAD",
+				"[1.1.0]: https://example.com/org/repo/-/compare/v1.0.0...v1.1.0",
+				"[1.0.0]: https://example.com/org/repo/-/tags/v1.0.0",
+				"[keep a changelog]: https://keepachangelog.com",
+			},
+		},
+		{
+			name: "bitbucket",
+			host: Bitbucket,
+			want: []string{
+				"[Unreleased]: https://example.com/org/repo/branches/compare/HEAD%0Dv1.1.0",
+				"[1.1.0]: https://example.com/org/repo/branches/compare/v1.1.0%0Dv1.0.0",
+				"[1.0.0]: https://example.com/org/repo/commits/tag/v1.0.0",
+				"[keep a changelog]: https://keepachangelog.com",
+			},
+		},
+		{
+			name:   "custom templates",
+			host:   Gitea,
+			custom: LinkTemplates{Compare: "{{.Repo}}/diff/{{.Previous}}..{{.Current}}"},
+			want: []string{
+				"[Unreleased]: https://example.com/org/repo/diff/v1.1.0..HEAD",
+				"[1.1.0]: https://example.com/org/repo/diff/v1.0.0..v1.1.0",
+				"[1.0.0]: https://example.com/org/repo/src/tag/v1.0.0",
+				"[keep a changelog]: https://keepachangelog.com",
+			},
+		},
+	}
+
+	cl, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := NewLinks("https://example.com/org/repo/", tt.host, tt.custom)
+			if err != nil {
+				t.Fatalf("NewLinks() error = %v", err)
+			}
+			got, err := links.footer(cl)
+			if err != nil {
+				t.Fatalf("footer() error = %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("footer() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	if _, err := NewLinks("https://example.com/org/repo", "sourceforge", LinkTemplates{}); !errors.Is(err, ErrUnknownHost) {
+		t.Errorf("NewLinks() error = %v, want %v", err, ErrUnknownHost)
+	}
+}
@@ -0,0 +1,364 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/WagnerMatos/semver/internal/clock"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+type Issue struct {
+	Line    int
+	Message string
+	// Fixable reports whether Fix can resolve the issue without guessing.
+	Fixable bool
+}
+
+type LintOptions struct {
+	RequireUnreleased bool
+	// Now is used to detect release dates in the future. Defaults to the
+	// current time.
+	Now time.Time
+	// DateLayout is the expected layout of release dates. Defaults to
+	// DefaultDateLayout.
+	DateLayout string
+}
+
+// semverPattern is the pattern recommended by the Semantic Versioning spec.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func Lint(cl *Changelog, opts LintOptions) []Issue {
+	if opts.Now.IsZero() {
+		opts.Now = clock.Wall.Now()
+	}
+	if opts.DateLayout == "" {
+		opts.DateLayout = DefaultDateLayout
+	}
+
+	var issues []Issue
+	add := func(line int, fixable bool, format string, args ...any) {
+		issues = append(issues, Issue{Line: line, Message: fmt.Sprintf(format, args...), Fixable: fixable})
+	}
+
+	hasUnreleased := false
+	seen := make(map[string]int)
+	for _, r := range cl.Releases {
+		if r.Version == Unreleased {
+			if hasUnreleased {
+				add(r.Line, false, "duplicate Unreleased section")
+			}
+			hasUnreleased = true
+		} else {
+			if first, ok := seen[r.Version]; ok {
+				add(r.Line, false, "duplicate version %s (first seen on line %d)", r.Version, first)
+			} else {
+				seen[r.Version] = r.Line
+			}
+
+			if !semverPattern.MatchString(r.Version) {
+				add(r.Line, false, "invalid version %q in heading", r.Version)
+			}
+
+			if r.Date == "" {
+				add(r.Line, false, "release %s has no date", r.Version)
+			} else if d, err := time.Parse(opts.DateLayout, r.Date); err != nil {
+				add(r.Line, false, "malformed date %q for %s, want %s", r.Date, r.Version, layoutHint(opts.DateLayout))
+			} else if d.After(opts.Now) {
+				add(r.Line, false, "release %s is dated in the future (%s)", r.Version, r.Date)
+			}
+		}
+
+		for _, s := range r.Sections {
+			if s.Name == "" {
+				continue
+			}
+			c, ok := LookupCategory(s.Name)
+			if !ok {
+				add(s.Line, false, "unknown category %q", s.Name)
+			} else if string(c) != s.Name {
+				add(s.Line, true, "category %q should be spelled %q", s.Name, c)
+			}
+			if len(s.Entries) == 0 && s.Text == "" {
+				add(s.Line, true, "empty section %q", s.Name)
+			}
+		}
+	}
+
+	issues = append(issues, lintOrder(cl)...)
+
+	if opts.RequireUnreleased && !hasUnreleased {
+		add(1, true, "missing Unreleased section")
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// descending reports whether the changelog lists its newest release first.
+// The direction is taken from the first two distinct versions; changelogs
+// with fewer default to newest first, as Keep a Changelog recommends.
+func descending(versions []*version.Version) bool {
+	for i := 1; i < len(versions); i++ {
+		if c := versions[i].Compare(versions[0]); c != 0 {
+			return c < 0
+		}
+	}
+	return true
+}
+
+func releaseVersions(cl *Changelog) ([]*Release, []*version.Version) {
+	var (
+		releases []*Release
+		versions []*version.Version
+	)
+	for _, r := range cl.Releases {
+		if !semverPattern.MatchString(r.Version) {
+			continue
+		}
+		v, err := version.ParseVersion(r.Version)
+		if err != nil {
+			continue
+		}
+		releases = append(releases, r)
+		versions = append(versions, v)
+	}
+	return releases, versions
+}
+
+// reorderable reports whether every release other than Unreleased has a
+// valid, unique version, which is what Fix needs to sort them safely.
+func reorderable(cl *Changelog) bool {
+	seen := make(map[string]bool)
+	for _, r := range cl.Releases {
+		if r.Version == Unreleased {
+			continue
+		}
+		if !semverPattern.MatchString(r.Version) || seen[r.Version] {
+			return false
+		}
+		seen[r.Version] = true
+	}
+	return true
+}
+
+func lintOrder(cl *Changelog) []Issue {
+	fixable := reorderable(cl)
+	releases, versions := releaseVersions(cl)
+	desc := descending(versions)
+	direction := "newest first"
+	if !desc {
+		direction = "oldest first"
+	}
+
+	var issues []Issue
+	for i := 1; i < len(versions); i++ {
+		c := versions[i].Compare(versions[i-1])
+		if c == 0 {
+			continue
+		}
+		if (c > 0) == desc {
+			issues = append(issues, Issue{
+				Line:    releases[i].Line,
+				Message: fmt.Sprintf("release %s is out of order after %s (changelog is %s)", versions[i], versions[i-1], direction),
+				Fixable: fixable,
+			})
+		}
+	}
+	return issues
+}
+
+// Fix applies the safe fixes reported by Lint: canonical category spelling,
+// removal of empty sections, release ordering and a missing Unreleased
+// section. Releases are only reordered when every version is valid and
+// unique, so nothing is ever merged or dropped.
+func Fix(src []byte, opts LintOptions) ([]byte, error) {
+	src, err := fixSections(src)
+	if err != nil {
+		return nil, err
+	}
+	if src, err = fixOrder(src); err != nil {
+		return nil, err
+	}
+	if opts.RequireUnreleased {
+		if src, err = fixUnreleased(src); err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+func splitLines(src []byte) []string {
+	s := strings.TrimSuffix(string(src), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func joinLines(lines []string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func fixSections(src []byte) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(src)
+	drop := make(map[int]bool)
+	for _, r := range cl.Releases {
+		for _, s := range r.Sections {
+			if s.Name == "" {
+				continue
+			}
+			if len(s.Entries) == 0 && s.Text == "" {
+				drop[s.Line] = true
+				continue
+			}
+			if c, ok := LookupCategory(s.Name); ok {
+				lines[s.Line-1] = "### " + string(c)
+			}
+		}
+	}
+
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if drop[i+1] {
+			// Also drop the blank line that separated the removed heading.
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+				drop[i+2] = true
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	return joinLines(out), nil
+}
+
+// releaseBlocks splits lines into the text before the first release, one
+// block per release heading and the trailing link reference definitions.
+// Trailing blank lines are removed from every part.
+func releaseBlocks(cl *Changelog, lines []string) (head []string, blocks [][]string, foot []string) {
+	end := len(lines)
+	if cl.footer > 0 {
+		end = cl.footer - 1
+		foot = lines[end:]
+	}
+	if len(cl.Releases) == 0 {
+		return trimBlank(lines[:end]), nil, foot
+	}
+
+	head = lines[:cl.Releases[0].Line-1]
+	blocks = make([][]string, len(cl.Releases))
+	for i, r := range cl.Releases {
+		blockEnd := end
+		if i+1 < len(cl.Releases) {
+			blockEnd = cl.Releases[i+1].Line - 1
+		}
+		blocks[i] = trimBlank(lines[r.Line-1 : blockEnd])
+	}
+	return trimBlank(head), blocks, foot
+}
+
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func assemble(head []string, blocks [][]string, foot []string) []byte {
+	out := append([]string{}, head...)
+	for _, block := range append(blocks, trimBlank(foot)) {
+		if len(block) == 0 {
+			continue
+		}
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+	return joinLines(out)
+}
+
+func fixOrder(src []byte) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	if len(lintOrder(cl)) == 0 || !reorderable(cl) {
+		return src, nil
+	}
+
+	var slots []int
+	for i, r := range cl.Releases {
+		if r.Version != Unreleased {
+			slots = append(slots, i)
+		}
+	}
+
+	_, versions := releaseVersions(cl)
+	desc := descending(versions)
+
+	head, blocks, foot := releaseBlocks(cl, splitLines(src))
+	perm := make([]int, len(slots))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		c := versions[perm[i]].Compare(versions[perm[j]])
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	sorted := make([][]string, len(blocks))
+	copy(sorted, blocks)
+	for k, slot := range slots {
+		sorted[slot] = blocks[slots[perm[k]]]
+	}
+	return assemble(head, sorted, foot), nil
+}
+
+func fixUnreleased(src []byte) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	if cl.Find(Unreleased) != nil {
+		return src, nil
+	}
+
+	head, blocks, foot := releaseBlocks(cl, splitLines(src))
+	unreleased := []string{"## [" + Unreleased + "]"}
+
+	_, versions := releaseVersions(cl)
+	if descending(versions) {
+		blocks = append([][]string{unreleased}, blocks...)
+	} else {
+		blocks = append(blocks, unreleased)
+	}
+	return assemble(head, blocks, foot), nil
+}
+
+// layoutHint describes a date layout in the form users know from ISO 8601.
+func layoutHint(layout string) string {
+	if layout == DefaultDateLayout {
+		return "YYYY-MM-DD"
+	}
+	return layout
+}
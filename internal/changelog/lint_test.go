@@ -0,0 +1,189 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+		opts  LintOptions
+		want  []Issue
+	}{
+		{
+			name: "clean changelog",
+			input: `## [Unreleased]
+
+## [1.1.0] - 2024-12-24
+### Added
+- Feature
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+`,
+			opts: LintOptions{RequireUnreleased: true},
+		},
+		{
+			name: "every kind of problem",
+			input: `## [1.0.0] - 2024-12-23
+### Added
+- One
+
+## [1.0.0] - 2024-12-24
+### fixed
+- Two
+### Misc
+- Three
+
+## [0.9.0] - 2099-01-01
+### Removed
+
+## [1.0] - 24/12/2024
+### Added
+- Four
+`,
+			opts: LintOptions{RequireUnreleased: true},
+			want: []Issue{
+				{Line: 1, Message: "missing Unreleased section", Fixable: true},
+				{Line: 5, Message: "duplicate version 1.0.0 (first seen on line 1)"},
+				{Line: 6, Message: `category "fixed" should be spelled "Fixed"`, Fixable: true},
+				{Line: 8, Message: `unknown category "Misc"`},
+				{Line: 11, Message: "release 0.9.0 is dated in the future (2099-01-01)"},
+				{Line: 12, Message: `empty section "Removed"`, Fixable: true},
+				{Line: 14, Message: `invalid version "1.0" in heading`},
+				{Line: 14, Message: `malformed date "24/12/2024" for 1.0, want YYYY-MM-DD`},
+			},
+		},
+		{
+			name: "custom date layout",
+			input: `## [1.1.0] - 24 Dec 2024
+- a
+## [1.0.0] - 2024-12-23
+- b
+`,
+			opts: LintOptions{DateLayout: "02 Jan 2006"},
+			want: []Issue{
+				{Line: 3, Message: `malformed date "2024-12-23" for 1.0.0, want 02 Jan 2006`},
+			},
+		},
+		{
+			name: "out of order in an oldest first changelog",
+			input: `## [0.1.0] - 2024-12-01
+- a
+## [0.3.0] - 2024-12-02
+- b
+## [0.2.0] - 2024-12-03
+- c
+`,
+			want: []Issue{
+				{Line: 5, Message: "release 0.2.0 is out of order after 0.3.0 (changelog is oldest first)", Fixable: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			tt.opts.Now = now
+			got := Lint(cl, tt.opts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lint() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Lint()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFix(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  LintOptions
+		want  string
+	}{
+		{
+			name: "sections, order and unreleased",
+			input: `# Changelog
+
+## [1.2.0] - 2024-12-25
+### Fixed
+- Three
+
+## [1.0.0] - 2024-12-23
+### added
+- One
+### Removed
+
+## [1.1.0] - 2024-12-24
+### Fixed
+- Two
+  details
+`,
+			opts: LintOptions{RequireUnreleased: true},
+			want: `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-12-25
+### Fixed
+- Three
+
+## [1.1.0] - 2024-12-24
+### Fixed
+- Two
+  details
+
+## [1.0.0] - 2024-12-23
+### Added
+- One
+`,
+		},
+		{
+			name: "duplicates are never reordered",
+			input: `## [1.0.0] - 2024-12-23
+- One
+
+## [1.1.0] - 2024-12-24
+- Two
+
+## [1.0.0] - 2024-12-25
+- Three
+`,
+			want: `## [1.0.0] - 2024-12-23
+- One
+
+## [1.1.0] - 2024-12-24
+- Two
+
+## [1.0.0] - 2024-12-25
+- Three
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fix([]byte(tt.input), tt.opts)
+			if err != nil {
+				t.Fatalf("Fix() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Fix() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
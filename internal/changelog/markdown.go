@@ -0,0 +1,333 @@
+package changelog
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	listItem = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(\s+)(.*)$`)
+	fence    = regexp.MustCompile("^\\s*(```+|~~~+)")
+	// heading is an ATX heading, so that a line opening with an issue
+	// reference such as #123 stays text.
+	heading = regexp.MustCompile(`^\s*(#{1,6})(\s|$)`)
+)
+
+// formatMarkdown prepares a long description to sit under a list item.
+// Line endings are normalised, paragraphs and list items are wrapped to
+// width (0 keeps the original line breaks), fenced code blocks, headings,
+// quotes and tables are kept verbatim, and every line is indented by n
+// spaces.
+func formatMarkdown(n, width int, s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if width > 0 {
+		width -= n
+	}
+
+	var (
+		out  []string
+		para *paragraph
+		// open is the fence of the code block being copied, which only a
+		// fence of the same character at least as long closes.
+		open string
+	)
+	flush := func() {
+		if para != nil {
+			out = append(out, para.lines(width)...)
+			para = nil
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if open != "" {
+			out = append(out, line)
+			if m := fence.FindStringSubmatch(line); m != nil && strings.HasPrefix(m[1], open) && strings.TrimSpace(line) == m[1] {
+				open = ""
+			}
+			continue
+		}
+
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+
+		case fence.MatchString(line):
+			flush()
+			open = fence.FindStringSubmatch(line)[1]
+			out = append(out, line)
+
+		case heading.MatchString(line) || strings.HasPrefix(trimmed, ">") || strings.HasPrefix(trimmed, "|"):
+			flush()
+			out = append(out, line)
+
+		case listItem.MatchString(line):
+			flush()
+			m := listItem.FindStringSubmatch(line)
+			marker := m[1] + m[2] + " "
+			para = &paragraph{first: marker, rest: strings.Repeat(" ", len(marker))}
+			para.add(m[4])
+
+		case para != nil:
+			para.add(trimmed)
+
+		case strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    "):
+			// Indented code block.
+			out = append(out, line)
+
+		default:
+			pad := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			para = &paragraph{first: pad, rest: pad}
+			para.add(trimmed)
+		}
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	for len(out) > 0 && out[0] == "" {
+		out = out[1:]
+	}
+	return indent(n, strings.Join(out, "\n"))
+}
+
+// paragraph collects the lines of a paragraph or list item. first prefixes
+// its first line and rest the others.
+type paragraph struct {
+	first, rest string
+	text        []string
+}
+
+func (p *paragraph) add(line string) {
+	p.text = append(p.text, line)
+}
+
+// lines returns the paragraph wrapped to width, or with its original line
+// breaks when width is 0.
+func (p *paragraph) lines(width int) []string {
+	if width <= 0 {
+		lines := make([]string, len(p.text))
+		for i, line := range p.text {
+			prefix := p.rest
+			if i == 0 {
+				prefix = p.first
+			}
+			lines[i] = prefix + line
+		}
+		return lines
+	}
+
+	var (
+		lines []string
+		cur   = p.first
+		empty = true
+	)
+	for _, word := range strings.Fields(strings.Join(p.text, " ")) {
+		if !empty && len(cur)+1+len(word) > width {
+			lines = append(lines, cur)
+			cur, empty = p.rest, true
+		}
+		if !empty {
+			cur += " "
+		}
+		cur += word
+		empty = false
+	}
+	return append(lines, cur)
+}
+
+// markdownHTML renders a long description as HTML. It knows the blocks
+// formatMarkdown keeps apart: paragraphs, list items, fenced and indented
+// code, headings, quotes and tables, which are shown preformatted. Code
+// spans are the only inline markup; all other text is escaped.
+func markdownHTML(s string) template.HTML {
+	var (
+		b strings.Builder
+		// kind and lines are the block being read: a paragraph ("p"), list
+		// item ("li"), quote ("blockquote"), table ("table") or indented
+		// code ("code").
+		kind  string
+		lines []string
+		// list is the list the items belong to, ul or ol.
+		list string
+		// open is the fence of the code block being read.
+		open string
+	)
+	flush := func() {
+		text := strings.Join(lines, "\n")
+		switch kind {
+		case "p", "li":
+			fmt.Fprintf(&b, "<%s>%s</%s>\n", kind, inlineHTML(text), kind)
+		case "blockquote":
+			fmt.Fprintf(&b, "<blockquote><p>%s</p></blockquote>\n", inlineHTML(text))
+		case "table":
+			fmt.Fprintf(&b, "<pre>%s</pre>\n", template.HTMLEscapeString(text))
+		case "code":
+			fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", template.HTMLEscapeString(text))
+		}
+		kind, lines = "", nil
+	}
+	closeList := func() {
+		flush()
+		if list != "" {
+			fmt.Fprintf(&b, "</%s>\n", list)
+			list = ""
+		}
+	}
+	// start begins a block of another kind, ending the one being read.
+	start := func(k string) {
+		if k != kind {
+			closeList()
+			kind = k
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if open != "" {
+			if m := fence.FindStringSubmatch(line); m != nil && strings.HasPrefix(m[1], open) && strings.TrimSpace(line) == m[1] {
+				flush()
+				open = ""
+				continue
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			closeList()
+
+		case fence.MatchString(line):
+			closeList()
+			open = fence.FindStringSubmatch(line)[1]
+			kind = "code"
+
+		case heading.MatchString(line):
+			closeList()
+			marks := heading.FindStringSubmatch(line)[1]
+			level := min(len(marks)+3, 6)
+			text := strings.TrimPrefix(trimmed, marks)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, inlineHTML(strings.TrimSpace(text)), level)
+
+		case strings.HasPrefix(trimmed, ">"):
+			start("blockquote")
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+
+		case strings.HasPrefix(trimmed, "|"):
+			start("table")
+			lines = append(lines, trimmed)
+
+		case listItem.MatchString(line):
+			m := listItem.FindStringSubmatch(line)
+			tag := "ul"
+			if strings.ContainsAny(m[2], ".)") {
+				tag = "ol"
+			}
+			if tag != list {
+				closeList()
+				fmt.Fprintf(&b, "<%s>\n", tag)
+				list = tag
+			}
+			flush()
+			kind, lines = "li", []string{m[4]}
+
+		case kind == "p" || kind == "li":
+			lines = append(lines, trimmed)
+
+		case strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    "):
+			start("code")
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+
+		default:
+			start("p")
+			lines = append(lines, trimmed)
+		}
+	}
+	closeList()
+
+	return template.HTML(strings.TrimSuffix(b.String(), "\n"))
+}
+
+var (
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^()\s]+)\)`)
+	mdStrong = regexp.MustCompile(`\*\*([^*]+)\*\*|\b__([^_]+)__\b`)
+	mdEm     = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*|\b_([^_\s](?:[^_]*[^_\s])?)_\b`)
+	// mdMention is an @handle, but not the middle of an email address.
+	mdMention = regexp.MustCompile(`(^|[^\w@/])@([A-Za-z0-9][A-Za-z0-9-]{0,38})\b`)
+)
+
+// inlineHTML renders the inline markup of a block: code spans, links,
+// emphasis and @handles. Handles link to GitHub profiles, as the ones this
+// tool writes are GitHub logins. All other text is escaped.
+func inlineHTML(s string) string {
+	var b strings.Builder
+	parts := strings.Split(s, "`")
+	for i, part := range parts {
+		switch {
+		case i%2 == 0:
+			b.WriteString(linksHTML(part))
+		case i == len(parts)-1:
+			// An unmatched backtick is plain text.
+			b.WriteString(linksHTML("`" + part))
+		default:
+			b.WriteString("<code>" + template.HTMLEscapeString(part) + "</code>")
+		}
+	}
+	return b.String()
+}
+
+// linksHTML renders the links in s and the markup around them. Links to
+// anything but web pages, mail addresses and relative paths stay text.
+func linksHTML(s string) string {
+	var b strings.Builder
+	for {
+		m := mdLink.FindStringSubmatchIndex(s)
+		if m == nil {
+			break
+		}
+		text, url := s[m[2]:m[3]], s[m[4]:m[5]]
+		if safeURL(url) {
+			b.WriteString(textHTML(s[:m[0]], true))
+			fmt.Fprintf(&b, `<a href="%s">%s</a>`, template.HTMLEscapeString(url), textHTML(text, false))
+		} else {
+			b.WriteString(textHTML(s[:m[1]], true))
+		}
+		s = s[m[1]:]
+	}
+	b.WriteString(textHTML(s, true))
+	return b.String()
+}
+
+func safeURL(url string) bool {
+	scheme, _, ok := strings.Cut(url, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// textHTML escapes s and renders its emphasis and, unless it is the text of
+// a link, its @handles.
+func textHTML(s string, mentions bool) string {
+	s = template.HTMLEscapeString(s)
+	if mentions {
+		s = mdMention.ReplaceAllString(s, `${1}<a href="https://github.com/${2}">@${2}</a>`)
+	}
+	s = mdStrong.ReplaceAllString(s, "<strong>$1$2</strong>")
+	return mdEm.ReplaceAllString(s, "<em>$1$2</em>")
+}
@@ -0,0 +1,197 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		in    string
+		want  string
+	}{
+		{
+			name: "paragraphs keep their line breaks without a width",
+			in:   "First line\r\nsecond line\r\n\r\n\r\nNext paragraph   ",
+			want: "  First line\n  second line\n\n  Next paragraph",
+		},
+		{
+			name:  "paragraphs are wrapped to the width",
+			width: 24,
+			in:    "The loader no longer races with\nthe watcher on startup.",
+			want:  "  The loader no longer\n  races with the watcher\n  on startup.",
+		},
+		{
+			name:  "list items get a hanging indent",
+			width: 20,
+			in:    "Steps:\n\n- rename the config file\n  - then run lint\n10. check the output carefully",
+			want:  "  Steps:\n\n  - rename the\n    config file\n    - then run lint\n  10. check the\n      output\n      carefully",
+		},
+		{
+			name:  "fenced code is kept verbatim",
+			width: 20,
+			in:    "Use:\n```go\nif err := run(ctx, logger, args); err != nil {\n\n\treturn err\n}\n```\nDone.",
+			want:  "  Use:\n  ```go\n  if err := run(ctx, logger, args); err != nil {\n\n  \treturn err\n  }\n  ```\n  Done.",
+		},
+		{
+			name:  "a longer fence holds shorter ones",
+			width: 20,
+			in:    "Example:\n````md\n```go\nfunc main() { run(ctx, logger, args) }\n```\n`````\nThe closing fence ends the block.",
+			want:  "  Example:\n  ````md\n  ```go\n  func main() { run(ctx, logger, args) }\n  ```\n  `````\n  The closing fence\n  ends the block.",
+		},
+		{
+			name:  "headings, quotes and tables are not wrapped",
+			width: 10,
+			in:    "#### A long heading here\n> a long quoted line\n| a | b | c | d |",
+			want:  "  #### A long heading here\n  > a long quoted line\n  | a | b | c | d |",
+		},
+		{
+			name:  "issue references are wrapped with their paragraph",
+			width: 40,
+			in:    "The loader raced with the watcher, see\n#123 and #124 for the full story of the race.",
+			want:  "  The loader raced with the watcher, see\n  #123 and #124 for the full story of\n  the race.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMarkdown(2, tt.width, tt.in); got != tt.want {
+				t.Errorf("formatMarkdown() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "paragraphs",
+			in:   "First line\nsecond <line>\n\nNext & last",
+			want: "<p>First line\nsecond &lt;line&gt;</p>\n<p>Next &amp; last</p>",
+		},
+		{
+			name: "lists",
+			in:   "Steps:\n\n- rename the config\n  file\n- run `lint`\n\n1. check\n2. release",
+			want: "<p>Steps:</p>\n<ul>\n<li>rename the config\nfile</li>\n<li>run <code>lint</code></li>\n</ul>\n<ol>\n<li>check</li>\n<li>release</li>\n</ol>",
+		},
+		{
+			name: "fenced code",
+			in:   "Use:\n````go\nif a < b {\n```\n}\n````\nDone.",
+			want: "<p>Use:</p>\n<pre><code>if a &lt; b {\n```\n}</code></pre>\n<p>Done.</p>",
+		},
+		{
+			name: "indented code",
+			in:   "Run:\n\n    semver lint\n\tsemver release",
+			want: "<p>Run:</p>\n<pre><code>semver lint\nsemver release</code></pre>",
+		},
+		{
+			name: "headings, quotes and tables",
+			in:   "## Upgrading\n> Back up\n> first.\n| a | b |\n| - | - |",
+			want: "<h5>Upgrading</h5>\n<blockquote><p>Back up\nfirst.</p></blockquote>\n<pre>| a | b |\n| - | - |</pre>",
+		},
+		{
+			name: "issue references are not headings",
+			in:   "#123 was caused by the loader.\nSee the PR.\n#\tOnly a mark",
+			want: "<p>#123 was caused by the loader.\nSee the PR.</p>\n<h4>Only a mark</h4>",
+		},
+		{
+			name: "unmatched backtick",
+			in:   "a ` b",
+			want: "<p>a ` b</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(markdownHTML(tt.in)); got != tt.want {
+				t.Errorf("markdownHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "entry",
+			in:   "**api:** add y ([#12](https://example.com/issues/12), PROJ-3) by @dev",
+			want: `<strong>api:</strong> add y (<a href="https://example.com/issues/12">#12</a>, PROJ-3) by <a href="https://github.com/dev">@dev</a>`,
+		},
+		{
+			name: "emphasis",
+			in:   "*really* _fast_ and __safe__, but snake_case_name stays",
+			want: "<em>really</em> <em>fast</em> and <strong>safe</strong>, but snake_case_name stays",
+		},
+		{
+			name: "code and escaping",
+			in:   "a < b & `c<d *e*`",
+			want: "a &lt; b &amp; <code>c&lt;d *e*</code>",
+		},
+		{
+			name: "email addresses are not handles",
+			in:   "mail dev@example.com",
+			want: "mail dev@example.com",
+		},
+		{
+			name: "unsafe links stay text",
+			in:   "[x](javascript:alert) [y](docs/y.md)",
+			want: `[x](javascript:alert) <a href="docs/y.md">y</a>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inlineHTML(tt.in); got != tt.want {
+				t.Errorf("inlineHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileService_UpdateMarkdown(t *testing.T) {
+	templates, err := NewTemplates("", TemplateSource{WrapWidth: 40})
+	if err != nil {
+		t.Fatalf("NewTemplates() error = %v", err)
+	}
+
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	s := New(changelogFile, WithTemplates(templates))
+	long := "Configure it with:\n\n```json\n{\"changelog\": {\"wrapWidth\": 80}}\n```\n\n- wrapping applies to paragraphs and list items only"
+	entries := []Entry{{Category: Added, Short: "Wrap long\ndescriptions", Long: long}}
+	if err := s.Update(version.Version{Major: 1, Minor: 1, Patch: 0}, version.Minor, entries, "", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Failed to read changelog: %v", err)
+	}
+	want := "### Added\n- Wrap long descriptions\n\n  Configure it with:\n\n  ```json\n" +
+		"  {\"changelog\": {\"wrapWidth\": 80}}\n  ```\n\n  - wrapping applies to paragraphs and\n    list items only\n"
+	if !strings.HasSuffix(string(content), want) {
+		t.Fatalf("Update() content =\n%s\nwant suffix:\n%s", content, want)
+	}
+
+	cl, err := ParseFile(changelogFile)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	e := cl.Releases[0].Sections[0].Entries[0]
+	if !strings.Contains(e.Long, "```json\n{\"changelog\"") || len(cl.Releases[0].Sections[0].Entries) != 1 {
+		t.Errorf("parsed entry = %+v", e)
+	}
+}
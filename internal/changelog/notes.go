@@ -0,0 +1,42 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Notes returns the section of the release ver as written in src, without
+// its heading, for use as a tag message or release description.
+func Notes(src []byte, ver string) (string, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return "", err
+	}
+
+	lines := splitLines(src)
+	end := len(lines) + 1
+	if cl.footer > 0 {
+		end = cl.footer
+	}
+	for i, r := range cl.Releases {
+		if r.Version != ver {
+			continue
+		}
+		if i+1 < len(cl.Releases) {
+			end = cl.Releases[i+1].Line
+		}
+		return strings.TrimSpace(strings.Join(lines[r.Line:end-1], "\n")), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrReleaseNotFound, ver)
+}
+
+// NotesFile applies Notes to the changelog at path.
+func NotesFile(path, ver string) (string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading changelog: %w", err)
+	}
+	return Notes(src, ver)
+}
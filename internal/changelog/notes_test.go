@@ -0,0 +1,48 @@
+package changelog
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNotes(t *testing.T) {
+	src := []byte(`# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-12-24
+### Added
+- Feature ([#12](https://github.com/org/repo/issues/12))
+
+  Details.
+
+## [1.0.0] - 2024-12-23
+### Fixed
+- Bug
+
+[1.1.0]: https://github.com/org/repo/compare/v1.0.0...v1.1.0
+`)
+
+	tests := []struct {
+		ver     string
+		want    string
+		wantErr error
+	}{
+		{ver: "1.1.0", want: "### Added\n- Feature ([#12](https://github.com/org/repo/issues/12))\n\n  Details."},
+		{ver: "1.0.0", want: "### Fixed\n- Bug"},
+		{ver: Unreleased, want: ""},
+		{ver: "2.0.0", wantErr: ErrReleaseNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ver, func(t *testing.T) {
+			got, err := Notes(src, tt.ver)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Notes() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Notes() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const Unreleased = "Unreleased"
+
+const yankedMarker = "[YANKED]"
+
+// Changelog is the parsed form of a changelog file. Line numbers are 1-based
+// and refer to the source the changelog was parsed from.
+type Changelog struct {
+	Title    string
+	Releases []*Release
+	Links    []*Link
+
+	// footer is the first line of the trailing block of link reference
+	// definitions, or 0 when the changelog has none.
+	footer int
+}
+
+type Release struct {
+	Version string
+	Date    string
+	Yanked  bool
+	// YankReason is the text following [YANKED] in the heading.
+	YankReason string
+	Line       int
+	Sections   []*Section
+}
+
+type Section struct {
+	Name    string
+	Line    int
+	Entries []*Entry
+	// Text is the content of a Migration or Contributors section, which
+	// has no entries.
+	Text string
+}
+
+// isText reports whether the section holds text instead of entries.
+func (s *Section) isText() bool {
+	c, _ := LookupCategory(s.Name)
+	return c == Migration || c == Contributors
+}
+
+// Entry is a single changelog bullet. Parsed entries carry the category of
+// the section they were found in; Author, Commit and Refs are only set on
+// entries that are about to be written.
+type Entry struct {
+	Category Category
+	Short    string
+	Long     string
+	Author   string
+	Commit   string
+	// Refs are issue, pull request and tracker references such as "#123"
+	// or "PROJ-42".
+	Refs []string
+	Line int
+}
+
+// Link is a markdown link reference definition such as
+// "[1.2.0]: https://github.com/org/repo/compare/v1.1.0...v1.2.0".
+type Link struct {
+	Name string
+	URL  string
+	Line int
+}
+
+var linkPattern = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)`)
+
+func ParseFile(path string) (*Changelog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening changelog: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+func Parse(r io.Reader) (*Changelog, error) {
+	cl := &Changelog{}
+
+	var (
+		release *Release
+		section *Section
+		entry   *Entry
+		body    []string
+	)
+
+	flush := func() {
+		if entry != nil {
+			entry.Long = strings.TrimSpace(strings.Join(body, "\n"))
+		} else if section != nil && section.isText() {
+			section.Text = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		entry = nil
+		body = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if m := linkPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			cl.Links = append(cl.Links, &Link{Name: m[1], URL: m[2], Line: lineNo})
+			if cl.footer == 0 {
+				cl.footer = lineNo
+			}
+			continue
+		}
+		if trimmed != "" {
+			cl.footer = 0
+		}
+
+		switch {
+		case strings.HasPrefix(line, "# ") && release == nil && cl.Title == "":
+			cl.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+
+		case strings.HasPrefix(line, "## "):
+			flush()
+			release = parseReleaseHeading(strings.TrimPrefix(line, "## "))
+			release.Line = lineNo
+			cl.Releases = append(cl.Releases, release)
+			section = nil
+
+		case strings.HasPrefix(line, "### ") && release != nil:
+			flush()
+			section = &Section{
+				Name: strings.TrimSpace(strings.TrimPrefix(line, "### ")),
+				Line: lineNo,
+			}
+			release.Sections = append(release.Sections, section)
+
+		case section != nil && section.isText():
+			body = append(body, line)
+
+		case (strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")) && release != nil:
+			flush()
+			if section == nil {
+				section = &Section{Line: lineNo}
+				release.Sections = append(release.Sections, section)
+			}
+			entry = &Entry{
+				Category: Category(section.Name),
+				Short:    strings.TrimSpace(line[2:]),
+				Line:     lineNo,
+			}
+			section.Entries = append(section.Entries, entry)
+
+		case entry != nil && (trimmed == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")):
+			body = append(body, strings.TrimPrefix(strings.TrimPrefix(line, "  "), "\t"))
+
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading changelog: %w", err)
+	}
+
+	return cl, nil
+}
+
+// parseReleaseHeading splits a heading such as "[1.2.0] - 2024-12-23 [YANKED]"
+// into its parts. Brackets around the version are optional and the date may
+// also be given in parentheses, as in "1.2.0 (2024-12-23)". Anything after
+// [YANKED] is the reason the release was yanked.
+func parseReleaseHeading(heading string) *Release {
+	heading = strings.TrimSpace(heading)
+	r := &Release{}
+
+	if before, reason, ok := strings.Cut(heading, yankedMarker); ok {
+		r.Yanked = true
+		r.YankReason = strings.TrimSpace(reason)
+		heading = strings.TrimSpace(before)
+	}
+
+	var ver, rest string
+	if end := strings.Index(heading, "]"); strings.HasPrefix(heading, "[") && end > 0 {
+		ver, rest = heading[1:end], heading[end+1:]
+	} else {
+		ver, rest, _ = strings.Cut(heading, " ")
+	}
+
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "-"))
+	if strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		rest = rest[1 : len(rest)-1]
+	}
+
+	r.Version = strings.TrimSpace(ver)
+	r.Date = strings.TrimSpace(rest)
+	return r
+}
+
+// Find returns the release with the given version, or nil.
+func (c *Changelog) Find(ver string) *Release {
+	for _, r := range c.Releases {
+		if r.Version == ver {
+			return r
+		}
+	}
+	return nil
+}
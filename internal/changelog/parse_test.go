@@ -0,0 +1,84 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# Changelog
+
+## [Unreleased]
+### Added
+- Pending feature
+
+## [1.1.0] - 2024-12-24 [YANKED]
+### Fixed
+- Fix loader
+  Long description
+  spanning two lines
+- Second fix
+
+## 1.0.0 - 2024-12-23
+### Major
+- Initial commit
+`
+
+	cl, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if cl.Title != "Changelog" {
+		t.Errorf("Title = %q, want %q", cl.Title, "Changelog")
+	}
+
+	if len(cl.Releases) != 3 {
+		t.Fatalf("len(Releases) = %d, want 3", len(cl.Releases))
+	}
+
+	tests := []struct {
+		name     string
+		release  *Release
+		version  string
+		date     string
+		yanked   bool
+		line     int
+		sections int
+	}{
+		{"unreleased", cl.Releases[0], Unreleased, "", false, 3, 1},
+		{"yanked release", cl.Releases[1], "1.1.0", "2024-12-24", true, 7, 1},
+		{"heading without brackets", cl.Releases[2], "1.0.0", "2024-12-23", false, 14, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.release
+			if r.Version != tt.version || r.Date != tt.date || r.Yanked != tt.yanked || r.Line != tt.line {
+				t.Errorf("release = {%q %q %v %d}, want {%q %q %v %d}",
+					r.Version, r.Date, r.Yanked, r.Line, tt.version, tt.date, tt.yanked, tt.line)
+			}
+			if len(r.Sections) != tt.sections {
+				t.Errorf("len(Sections) = %d, want %d", len(r.Sections), tt.sections)
+			}
+		})
+	}
+
+	fixed := cl.Releases[1].Sections[0]
+	if fixed.Name != "Fixed" || len(fixed.Entries) != 2 {
+		t.Fatalf("section = %q with %d entries, want Fixed with 2", fixed.Name, len(fixed.Entries))
+	}
+	if got, want := fixed.Entries[0].Long, "Long description\nspanning two lines"; got != want {
+		t.Errorf("Long = %q, want %q", got, want)
+	}
+	if fixed.Entries[1].Long != "" {
+		t.Errorf("Long = %q, want empty", fixed.Entries[1].Long)
+	}
+
+	if cl.Find("1.0.0") != cl.Releases[2] {
+		t.Errorf("Find(1.0.0) did not return the matching release")
+	}
+	if cl.Find("9.9.9") != nil {
+		t.Errorf("Find(9.9.9) = non-nil, want nil")
+	}
+}
@@ -0,0 +1,171 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// IssuePrefix is the RefURLs key for issue and pull request numbers such as
+// #123. Any other key is a tracker project key, so "PROJ" matches PROJ-42.
+const IssuePrefix = "#"
+
+// Ref is an issue, pull request or tracker reference. URL is empty when no
+// pattern matches it.
+type Ref struct {
+	ID  string
+	URL string
+}
+
+type refData struct {
+	Repo   string
+	ID     string
+	Number string
+}
+
+// RefURLs turns references into links.
+type RefURLs struct {
+	repo     string
+	patterns map[string]*template.Template
+}
+
+// NewRefURLs links issue numbers through the repository host when links is
+// set. custom maps IssuePrefix or a tracker key to a URL template that
+// receives {{.Repo}}, {{.ID}} (such as PROJ-42) and {{.Number}} (42).
+func NewRefURLs(links *Links, custom map[string]string) (*RefURLs, error) {
+	r := &RefURLs{patterns: make(map[string]*template.Template)}
+	if links != nil {
+		r.repo = links.Repo
+		if links.Issue != nil {
+			r.patterns[IssuePrefix] = links.Issue
+		}
+	}
+
+	for key, pattern := range custom {
+		t, err := template.New(key).Option("missingkey=error").Parse(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parsing url template for %s: %w", key, err)
+		}
+		r.patterns[key] = t
+	}
+	return r, nil
+}
+
+// Keys returns the configured tracker keys.
+func (r *RefURLs) Keys() []string {
+	if r == nil {
+		return nil
+	}
+	var keys []string
+	for key := range r.patterns {
+		if key != IssuePrefix {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Resolve looks up the link for each reference.
+func (r *RefURLs) Resolve(ids []string) []Ref {
+	refs := make([]Ref, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, Ref{ID: id, URL: r.url(id)})
+	}
+	return refs
+}
+
+func (r *RefURLs) url(id string) string {
+	if r == nil {
+		return ""
+	}
+
+	key, number := IssuePrefix, strings.TrimPrefix(id, IssuePrefix)
+	if !strings.HasPrefix(id, IssuePrefix) {
+		var ok bool
+		if key, number, ok = strings.Cut(id, "-"); !ok {
+			return ""
+		}
+	}
+
+	t, ok := r.patterns[key]
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, refData{Repo: r.repo, ID: id, Number: number}); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+var (
+	issueRef   = regexp.MustCompile(`(?:^|[^\w&/])(#\d+)\b`)
+	trackerRef = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+-\d+)\b`)
+	refSuffix  = regexp.MustCompile(`\s*\(\s*(?:#\d+|[A-Z][A-Z0-9_]+-\d+)(?:\s*,\s*(?:#\d+|[A-Z][A-Z0-9_]+-\d+))*\s*\)\s*$`)
+)
+
+// ExtractRefs finds issue numbers and tracker references in text. Only the
+// given tracker keys are recognised; nil accepts any key, which suits
+// trailers such as "Fixes: PROJ-42" where the context is unambiguous.
+func ExtractRefs(text string, keys []string) []string {
+	var refs []string
+	for _, m := range issueRef.FindAllStringSubmatch(text, -1) {
+		refs = appendRef(refs, m[1])
+	}
+	for _, m := range trackerRef.FindAllStringSubmatch(text, -1) {
+		key, _, _ := strings.Cut(m[1], "-")
+		if keys == nil || slices.Contains(keys, key) {
+			refs = appendRef(refs, m[1])
+		}
+	}
+	return refs
+}
+
+// SplitRefs extracts the references from a short description and drops a
+// trailing list of them, such as the " (#123)" GitHub appends to squash
+// merges, since the renderer adds them back as links.
+func SplitRefs(short string, keys []string) (string, []string) {
+	refs := ExtractRefs(short, keys)
+	if loc := refSuffix.FindStringIndex(short); loc != nil {
+		suffix := short[loc[0]:]
+		if len(ExtractRefs(suffix, keys)) == strings.Count(suffix, ",")+1 {
+			short = strings.TrimSpace(short[:loc[0]])
+		}
+	}
+	return short, refs
+}
+
+// MergeRefs appends the references in more that refs doesn't already hold.
+func MergeRefs(refs []string, more ...string) []string {
+	for _, ref := range more {
+		refs = appendRef(refs, ref)
+	}
+	return refs
+}
+
+func appendRef(refs []string, ref string) []string {
+	if slices.Contains(refs, ref) {
+		return refs
+	}
+	return append(refs, ref)
+}
+
+// formatRefs renders references as "([#123](url), PROJ-42)".
+func formatRefs(refs []Ref) string {
+	if len(refs) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.URL != "" {
+			parts = append(parts, fmt.Sprintf("[%s](%s)", ref.ID, ref.URL))
+		} else {
+			parts = append(parts, ref.ID)
+		}
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
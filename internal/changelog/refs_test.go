@@ -0,0 +1,97 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestExtractRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		keys []string
+		want []string
+	}{
+		{"issue numbers", "Fix #12 and #3, again #12", nil, []string{"#12", "#3"}},
+		{"anchors and urls are ignored", "See docs/#15 and page&#39;s", nil, nil},
+		{"configured tracker keys only", "PROJ-42 handles UTF-8 and OTHER-1", []string{"PROJ"}, []string{"PROJ-42"}},
+		{"any key when unrestricted", "PROJ-42, OTHER-1", nil, []string{"PROJ-42", "OTHER-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractRefs(tt.text, tt.keys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractRefs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitRefs(t *testing.T) {
+	tests := []struct {
+		short     string
+		wantShort string
+		wantRefs  []string
+	}{
+		{"Fix race in loader (#123)", "Fix race in loader", []string{"#123"}},
+		{"Fix race (#1, PROJ-2)", "Fix race", []string{"#1", "PROJ-2"}},
+		{"Fix #4 in the middle", "Fix #4 in the middle", []string{"#4"}},
+		{"Keep unknown keys (OTHER-2)", "Keep unknown keys (OTHER-2)", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.short, func(t *testing.T) {
+			short, refs := SplitRefs(tt.short, []string{"PROJ"})
+			if short != tt.wantShort || !reflect.DeepEqual(refs, tt.wantRefs) {
+				t.Errorf("SplitRefs() = %q, %q; want %q, %q", short, refs, tt.wantShort, tt.wantRefs)
+			}
+		})
+	}
+}
+
+func TestFileService_UpdateRefs(t *testing.T) {
+	links, err := NewLinks("https://github.com/org/repo", "", LinkTemplates{})
+	if err != nil {
+		t.Fatalf("NewLinks() error = %v", err)
+	}
+	refs, err := NewRefURLs(links, map[string]string{"PROJ": "https://jira.example.com/browse/{{.ID}}"})
+	if err != nil {
+		t.Fatalf("NewRefURLs() error = %v", err)
+	}
+	if keys := refs.Keys(); !reflect.DeepEqual(keys, []string{"PROJ"}) {
+		t.Errorf("Keys() = %q", keys)
+	}
+
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	s := New(changelogFile, WithRefURLs(refs))
+	entries := []Entry{
+		{Category: Fixed, Short: "Fix race in loader", Author: "@alice", Refs: []string{"#123"}},
+		{Category: Fixed, Short: "Handle timeouts", Author: "bob", Refs: []string{"PROJ-42", "OTHER-1"}},
+		{Category: Fixed, Short: "Plain"},
+	}
+	if err := s.Update(version.Version{Major: 1, Minor: 0, Patch: 1}, version.Patch, entries, "", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Failed to read changelog: %v", err)
+	}
+	want := `### Fixed
+- Fix race in loader ([#123](https://github.com/org/repo/issues/123)) by @alice
+- Handle timeouts ([PROJ-42](https://jira.example.com/browse/PROJ-42), OTHER-1) by bob
+- Plain
+`
+	if !strings.HasSuffix(string(content), want) {
+		t.Errorf("Update() content =\n%s\nwant suffix\n%s", content, want)
+	}
+
+	if _, err := NewRefURLs(nil, map[string]string{"PROJ": "{{"}); err == nil {
+		t.Error("NewRefURLs() with a broken template succeeded")
+	}
+}
@@ -0,0 +1,79 @@
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Render writes cl in this tool's changelog format. Entries are grouped by
+// their category, so entries parsed from another format can be written
+// without their original section headings.
+func Render(w io.Writer, cl *Changelog) error {
+	var b strings.Builder
+
+	title := cl.Title
+	if title == "" {
+		title = "Changelog"
+	}
+	fmt.Fprintf(&b, "# %s\n", title)
+
+	for _, r := range cl.Releases {
+		b.WriteString("\n")
+		if r.Version == Unreleased {
+			fmt.Fprintf(&b, "## [%s]\n", Unreleased)
+		} else {
+			fmt.Fprintf(&b, "## [%s]", r.Version)
+			if r.Date != "" {
+				fmt.Fprintf(&b, " - %s", r.Date)
+			}
+			if r.Yanked {
+				b.WriteString(" " + yankedMarker)
+				if r.YankReason != "" {
+					b.WriteString(" " + r.YankReason)
+				}
+			}
+			b.WriteString("\n")
+		}
+
+		var entries []Entry
+		texts := make(map[Category][]string)
+		for _, s := range r.Sections {
+			for _, e := range s.Entries {
+				entries = append(entries, *e)
+			}
+			if s.Text != "" {
+				c, _ := LookupCategory(s.Name)
+				texts[c] = append(texts[c], s.Text)
+			}
+		}
+
+		order, groups := groupByCategory(entries, Changed)
+		for _, c := range order {
+			fmt.Fprintf(&b, "### %s\n", c)
+			for _, e := range groups[c] {
+				fmt.Fprintf(&b, "- %s\n", e.Short)
+				if e.Long != "" {
+					fmt.Fprintf(&b, "\n%s\n", formatMarkdown(2, 0, e.Long))
+				}
+			}
+		}
+		for _, c := range []Category{Migration, Contributors} {
+			if len(texts[c]) > 0 {
+				fmt.Fprintf(&b, "### %s\n%s\n", c, strings.Join(texts[c], "\n\n"))
+			}
+		}
+	}
+
+	if len(cl.Links) > 0 {
+		b.WriteString("\n")
+		for _, l := range cl.Links {
+			fmt.Fprintf(&b, "[%s]: %s\n", l.Name, l.URL)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	input := `# Changelog
+
+## [Unreleased]
+### Added
+- Pending feature
+
+## [1.1.0] - 2024-12-24 [YANKED]
+### Fixed
+- Fix loader
+  Long description
+### Added
+- New flag
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`
+
+	want := `# Changelog
+
+## [Unreleased]
+### Added
+- Pending feature
+
+## [1.1.0] - 2024-12-24 [YANKED]
+### Added
+- New flag
+### Fixed
+- Fix loader
+
+  Long description
+
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit
+
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`
+
+	cl, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var b strings.Builder
+	if err := Render(&b, cl); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Render() =\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,247 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var (
+	ErrUnknownPreset   = errors.New("unknown template preset")
+	ErrInvalidTemplate = errors.New("invalid changelog template")
+)
+
+// ReleaseData is passed to the heading template.
+type ReleaseData struct {
+	Version         string
+	PreviousVersion string
+	Date            string
+	Type            version.Type
+}
+
+// SectionData is passed to the section template, once per category.
+type SectionData struct {
+	ReleaseData
+	Category Category
+}
+
+// EntryData is passed to the entry template. Commit is empty for entries
+// that were written by hand. Author is ready to print: it is an @handle only
+// when the author is known to have one.
+type EntryData struct {
+	ReleaseData
+	Category Category
+	Short    string
+	Long     string
+	Author   string
+	Commit   string
+	Refs     []Ref
+}
+
+type TemplateSource struct {
+	Heading string
+	Section string
+	Entry   string
+	// WrapWidth is the column the markdown function wraps long
+	// descriptions at; 0 keeps their line breaks.
+	WrapWidth int
+}
+
+const (
+	defaultHeading = "## [{{.Version}}] - {{.Date}}"
+	defaultSection = "### {{.Category}}"
+	defaultEntry   = "- {{.Short}}{{with .Refs}} {{refs .}}{{end}}{{with .Author}} by {{.}}{{end}}" +
+		"{{if .Long}}\n\n{{markdown 2 .Long}}{{end}}"
+)
+
+var Presets = map[string]TemplateSource{
+	"default": {
+		Heading: defaultHeading,
+		Section: defaultSection,
+		Entry:   defaultEntry,
+	},
+	"compact": {
+		Heading: "## {{.Version}} ({{.Date}})",
+		Section: defaultSection,
+		Entry:   "- {{.Short}}{{with .Refs}} {{refs .}}{{end}}",
+	},
+	"detailed": {
+		Heading: defaultHeading,
+		Section: defaultSection,
+		Entry: "- {{.Short}}{{with .Refs}} {{refs .}}{{end}}{{if .Commit}} ({{short .Commit}}){{end}}{{with .Author}} by {{.}}{{end}}" +
+			"{{if .Long}}\n\n{{markdown 2 .Long}}{{end}}",
+	},
+	"since-previous": {
+		Heading: "## [{{.Version}}] - {{.Date}}{{if .PreviousVersion}}\n_Changes since {{.PreviousVersion}}._{{end}}",
+		Section: defaultSection,
+		Entry:   defaultEntry,
+	},
+}
+
+// PresetNames returns the built-in preset names in a stable order.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var templateFuncs = template.FuncMap{
+	"title": func(t version.Type) string {
+		s := string(t)
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"short": func(sha string) string {
+		if len(sha) > 7 {
+			return sha[:7]
+		}
+		return sha
+	},
+	"indent": indent,
+	"refs":   formatRefs,
+}
+
+// indent prefixes every non-empty line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var defaultTemplates = func() *Templates {
+	t, err := NewTemplates("", TemplateSource{})
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+type Templates struct {
+	heading *template.Template
+	section *template.Template
+	entry   *template.Template
+}
+
+// NewTemplates starts from a preset (the default one when empty) and
+// replaces its heading, section or entry template with any non-empty
+// override. The
+// result is executed against sample data so that broken templates are
+// reported before a changelog is written.
+func NewTemplates(preset string, override TemplateSource) (*Templates, error) {
+	if preset == "" {
+		preset = "default"
+	}
+	src, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownPreset, preset, strings.Join(PresetNames(), ", "))
+	}
+	if override.Heading != "" {
+		src.Heading = override.Heading
+	}
+	if override.Section != "" {
+		src.Section = override.Section
+	}
+	if override.Entry != "" {
+		src.Entry = override.Entry
+	}
+
+	funcs := template.FuncMap{
+		"markdown": func(n int, s string) string {
+			return formatMarkdown(n, override.WrapWidth, s)
+		},
+	}
+
+	heading, err := template.New("heading").Funcs(templateFuncs).Funcs(funcs).Parse(src.Heading)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	section, err := template.New("section").Funcs(templateFuncs).Funcs(funcs).Parse(src.Section)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	entry, err := template.New("entry").Funcs(templateFuncs).Funcs(funcs).Parse(src.Entry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	t := &Templates{heading: heading, section: section, entry: entry}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Templates) validate() error {
+	sample := EntryData{
+		ReleaseData: ReleaseData{
+			Version:         "1.2.0",
+			PreviousVersion: "1.1.0",
+			Date:            "2024-12-24",
+			Type:            version.Minor,
+		},
+		Category: Added,
+		Short:    "Short description",
+		Long:     "Long description",
+		Author:   "Jane Doe",
+		Commit:   "0123456789abcdef0123456789abcdef01234567",
+		Refs:     []Ref{{ID: "#123", URL: "https://example.com/issues/123"}},
+	}
+
+	heading, err := t.Heading(sample.ReleaseData)
+	if err != nil {
+		return err
+	}
+	if len(heading) == 0 || !strings.HasPrefix(heading[0], "## ") {
+		return fmt.Errorf("%w: heading must start with \"## \"", ErrInvalidTemplate)
+	}
+	if r := parseReleaseHeading(strings.TrimPrefix(heading[0], "## ")); r.Version != sample.Version {
+		return fmt.Errorf("%w: heading %q does not start with the version", ErrInvalidTemplate, heading[0])
+	}
+
+	if _, err := t.Section(SectionData{ReleaseData: sample.ReleaseData, Category: Added}); err != nil {
+		return err
+	}
+	if _, err := t.Entry(sample); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Heading renders the release heading as lines.
+func (t *Templates) Heading(data ReleaseData) ([]string, error) {
+	return execute(t.heading, data)
+}
+
+// Section renders a category heading as lines.
+func (t *Templates) Section(data SectionData) ([]string, error) {
+	return execute(t.section, data)
+}
+
+// Entry renders a single entry as lines.
+func (t *Templates) Entry(data EntryData) ([]string, error) {
+	return execute(t.entry, data)
+}
+
+func execute(t *template.Template, data any) ([]string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return splitLines([]byte(b.String())), nil
+}
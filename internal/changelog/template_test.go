@@ -0,0 +1,103 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestNewTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		preset   string
+		override TemplateSource
+		wantErr  error
+	}{
+		{name: "default preset"},
+		{name: "compact preset", preset: "compact"},
+		{name: "detailed preset", preset: "detailed"},
+		{name: "since-previous preset", preset: "since-previous"},
+		{
+			name:     "entry override",
+			override: TemplateSource{Entry: "* {{upper .Short}} ({{.Type}})"},
+		},
+		{
+			name:    "unknown preset",
+			preset:  "fancy",
+			wantErr: ErrUnknownPreset,
+		},
+		{
+			name:     "syntax error",
+			override: TemplateSource{Entry: "- {{.Short"},
+			wantErr:  ErrInvalidTemplate,
+		},
+		{
+			name:     "unknown field",
+			override: TemplateSource{Entry: "- {{.Summary}}"},
+			wantErr:  ErrInvalidTemplate,
+		},
+		{
+			name:     "section override",
+			override: TemplateSource{Section: "### {{upper (print .Category)}}"},
+		},
+		{
+			name:     "heading is not a release heading",
+			override: TemplateSource{Heading: "Release {{.Version}}"},
+			wantErr:  ErrInvalidTemplate,
+		},
+		{
+			name:     "heading without the version",
+			override: TemplateSource{Heading: "## {{.Date}}"},
+			wantErr:  ErrInvalidTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTemplates(tt.preset, tt.override)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileService_UpdateWithTemplates(t *testing.T) {
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	initial := "## [1.0.0] - 2024-12-23\n### Major\n- Initial commit\n"
+	if err := os.WriteFile(changelogFile, []byte(initial), 0644); err != nil {
+		t.Fatalf("Failed to write changelog: %v", err)
+	}
+
+	templates, err := NewTemplates("since-previous", TemplateSource{
+		Entry: "- {{.Short}} [{{.Type}}]{{if .Long}}\n{{indent 4 .Long}}{{end}}",
+	})
+	if err != nil {
+		t.Fatalf("NewTemplates() error = %v", err)
+	}
+
+	s := New(changelogFile, WithTemplates(templates))
+	entries := []Entry{{Short: "feature", Long: "line one\nline two"}}
+	if err := s.Update(version.Version{Major: 1, Minor: 1, Patch: 0}, version.Minor, entries, "", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Failed to read changelog file: %v", err)
+	}
+
+	expected := []string{
+		"_Changes since 1.0.0._\n### Added\n",
+		"- feature [minor]\n    line one\n    line two\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("Update() content missing %q:\n%s", exp, content)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+const upgradingTitle = "# Upgrading"
+
+func upgradingHeading(v version.Version) string {
+	return fmt.Sprintf("## Upgrading to %d.0.0", v.Major)
+}
+
+// addUpgrading adds migration notes to the upgrade guide at path, newest
+// major version first. Notes for a major version that already has a section
+// are appended to it.
+func addUpgrading(path string, v version.Version, notes string) error {
+	src, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading upgrade guide: %w", err)
+	}
+
+	lines := splitLines(src)
+	if len(lines) == 0 {
+		lines = []string{upgradingTitle}
+	}
+
+	heading := upgradingHeading(v)
+	notesLines := strings.Split(notes, "\n")
+
+	// Find the end of an existing section for this major version, or the
+	// first release section to insert above.
+	at, existing := len(lines), false
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if existing {
+			at = i
+			break
+		}
+		if strings.TrimSpace(line) == heading {
+			existing = true
+			continue
+		}
+		at = i
+		break
+	}
+
+	var insert []string
+	if existing {
+		insert = append([]string{""}, notesLines...)
+	} else {
+		insert = append([]string{"", heading, ""}, notesLines...)
+	}
+
+	out := slices.Concat(trimBlank(lines[:at]), insert, []string{""}, lines[at:])
+
+	if err := os.WriteFile(path, joinLines(trimBlank(out)), 0644); err != nil {
+		return fmt.Errorf("writing upgrade guide: %w", err)
+	}
+	return nil
+}
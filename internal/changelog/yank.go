@@ -0,0 +1,59 @@
+package changelog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrReleaseNotFound = errors.New("release not found")
+	ErrAlreadyYanked   = errors.New("release already yanked")
+)
+
+// Yank marks the release heading for ver as [YANKED], followed by the
+// reason. Nothing else in src changes.
+func Yank(src []byte, ver, reason string) ([]byte, error) {
+	cl, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	r := cl.Find(ver)
+	if r == nil || ver == Unreleased {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, ver)
+	}
+	if r.Yanked {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyYanked, ver)
+	}
+
+	lines := splitLines(src)
+	heading := strings.TrimRight(lines[r.Line-1], " \t") + " " + yankedMarker
+	if reason = strings.Join(strings.Fields(reason), " "); reason != "" {
+		heading += " " + reason
+	}
+	lines[r.Line-1] = heading
+
+	return joinLines(lines), nil
+}
+
+// YankedFile lists the versions marked [YANKED] in the changelog at path.
+// A missing changelog has none.
+func YankedFile(path string) ([]string, error) {
+	cl, err := ParseFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var yanked []string
+	for _, r := range cl.Releases {
+		if r.Yanked {
+			yanked = append(yanked, r.Version)
+		}
+	}
+	return yanked, nil
+}
@@ -0,0 +1,109 @@
+package changelog
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestYank(t *testing.T) {
+	src := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-12-24
+### Fixed
+- Fix loader
+
+## [1.0.0] - 2024-12-23 [YANKED]
+### Added
+- Initial commit
+`
+
+	tests := []struct {
+		name    string
+		version string
+		reason  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "marks heading with reason",
+			version: "1.1.0",
+			reason:  "Corrupts the cache\non upgrade",
+			want: `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-12-24 [YANKED] Corrupts the cache on upgrade
+### Fixed
+- Fix loader
+
+## [1.0.0] - 2024-12-23 [YANKED]
+### Added
+- Initial commit
+`,
+		},
+		{
+			name:    "unknown version",
+			version: "2.0.0",
+			wantErr: ErrReleaseNotFound,
+		},
+		{
+			name:    "unreleased can't be yanked",
+			version: Unreleased,
+			wantErr: ErrReleaseNotFound,
+		},
+		{
+			name:    "already yanked",
+			version: "1.0.0",
+			wantErr: ErrAlreadyYanked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Yank([]byte(src), tt.version, tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Yank() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Yank() =\n%s\nwant:\n%s", got, tt.want)
+			}
+
+			cl, err := Parse(bytes.NewReader(got))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			r := cl.Find(tt.version)
+			if !r.Yanked || r.YankReason != "Corrupts the cache on upgrade" || r.Date != "2024-12-24" {
+				t.Errorf("parsed release = %+v", r)
+			}
+		})
+	}
+}
+
+func TestYankedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if got, err := YankedFile(path); err != nil || got != nil {
+		t.Errorf("YankedFile() of a missing changelog = %q, %v, want none", got, err)
+	}
+
+	src := "## [1.2.0] - 2024-12-25\n\n## [1.1.0] - 2024-12-24 [YANKED] Breaks the loader\n\n## [1.0.0] - 2024-12-23 [YANKED]\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := YankedFile(path)
+	if err != nil {
+		t.Fatalf("YankedFile() error = %v", err)
+	}
+	if want := []string{"1.1.0", "1.0.0"}; !slices.Equal(got, want) {
+		t.Errorf("YankedFile() = %q, want %q", got, want)
+	}
+}
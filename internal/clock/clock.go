@@ -0,0 +1,51 @@
+// Package clock supplies the time used for release dates, so that a release
+// can be rebuilt byte for byte.
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SourceDateEpoch is the reproducible-builds variable holding a Unix
+// timestamp to use instead of the current time.
+const SourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+var ErrInvalidEpoch = errors.New("invalid " + SourceDateEpoch)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to a Clock.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// Wall is the current time in UTC.
+var Wall Clock = Func(func() time.Time { return time.Now().UTC() })
+
+// Fixed always returns t in UTC.
+func Fixed(t time.Time) Clock {
+	t = t.UTC()
+	return Func(func() time.Time { return t })
+}
+
+// FromEnv returns a clock fixed at SOURCE_DATE_EPOCH when it is set, and
+// Wall otherwise.
+func FromEnv() (Clock, error) {
+	v, ok := os.LookupEnv(SourceDateEpoch)
+	if !ok || v == "" {
+		return Wall, nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || secs < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEpoch, v)
+	}
+	return Fixed(time.Unix(secs, 0)), nil
+}
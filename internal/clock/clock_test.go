@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		epoch   string
+		want    time.Time
+		wantErr error
+	}{
+		{name: "unset falls back to the wall clock", epoch: ""},
+		{name: "epoch is used in UTC", epoch: "1735689599", want: time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{name: "zero epoch", epoch: "0", want: time.Unix(0, 0).UTC()},
+		{name: "not a number", epoch: "yesterday", wantErr: ErrInvalidEpoch},
+		{name: "negative", epoch: "-1", wantErr: ErrInvalidEpoch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(SourceDateEpoch, tt.epoch)
+
+			c, err := FromEnv()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromEnv() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := c.Now()
+			if got.Location() != time.UTC {
+				t.Errorf("Now() location = %v, want UTC", got.Location())
+			}
+			if !tt.want.IsZero() && !got.Equal(tt.want) {
+				t.Errorf("Now() = %v, want %v", got, tt.want)
+			}
+			if tt.want.IsZero() && time.Since(got) > time.Minute {
+				t.Errorf("Now() = %v, want the current time", got)
+			}
+		})
+	}
+}
+
+func TestFixed(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	c := Fixed(time.Date(2025, 1, 1, 8, 0, 0, 0, loc))
+	if got := c.Now().Format("2006-01-02"); got != "2024-12-31" {
+		t.Errorf("Now() date = %s, want 2024-12-31", got)
+	}
+}
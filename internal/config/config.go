@@ -1,13 +1,135 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
+// FileName is the optional per-repository configuration file, read from the
+// working directory.
+const FileName = ".semver.json"
+
 type Config struct {
-	VersionFile   string
-	ChangelogFile string
+	VersionFile   string `json:"versionFile"`
+	ChangelogFile string `json:"changelogFile"`
+	// UpgradingFile, when set, also collects the migration notes of major
+	// releases into an upgrade guide such as UPGRADING.md.
+	UpgradingFile string `json:"upgradingFile"`
+	// ReleaseFiles are committed with the version file and changelog, such
+	// as manifests kept in sync with the version or generated files. They
+	// may have changes when the release starts.
+	ReleaseFiles []string        `json:"releaseFiles"`
+	Changelog    ChangelogConfig `json:"changelog"`
+	Tag          TagConfig       `json:"tag"`
+	Push         PushConfig      `json:"push"`
+	// IncludeStaged commits changes that were staged before a release along
+	// with it. By default the release refuses to run when there are any.
+	IncludeStaged bool                `json:"includeStaged"`
+	Preflight     PreflightConfig     `json:"preflight"`
+	ReleaseCommit ReleaseCommitConfig `json:"releaseCommit"`
+	// GitBackend is "exec" to run the git binary or "go-git" to work
+	// in-process. By default the binary is used when it is installed.
+	GitBackend string `json:"gitBackend"`
+}
+
+type ReleaseCommitConfig struct {
+	// Message is a Go text/template for the release commit message. It
+	// receives .Version, .Tag, .Type and .Entries.
+	Message string `json:"message"`
+	// Trailers are appended to the message in order. Their values are
+	// templates like Message; trailers that render empty are left out.
+	Trailers []TrailerConfig `json:"trailers"`
+	// SignOff adds a Signed-off-by trailer for the committer, as git
+	// commit -s does.
+	SignOff bool `json:"signOff"`
+}
+
+// TrailerConfig is a trailer such as "Co-authored-by: Jane <jane@example.com>".
+type TrailerConfig struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+type PreflightConfig struct {
+	// Branches are the patterns of branches releases may be made from,
+	// such as "release/*". An empty list allows any branch.
+	Branches []string `json:"branches"`
+	// Skip names checks that never run: clean, branch, upstream, tag or
+	// identity.
+	Skip []string `json:"skip"`
+}
+
+type PushConfig struct {
+	// Remote receives the release commit and tag; origin by default.
+	Remote string `json:"remote"`
+	// Branch is the remote branch the release commit is pushed to. When
+	// empty it is the branch of the same name as the current one.
+	Branch string `json:"branch"`
+}
+
+type TagConfig struct {
+	// Annotate creates annotated tags whose message is the release's
+	// changelog section.
+	Annotate bool `json:"annotate"`
+	// Sign signs release tags, which makes them annotated too. SigningKey
+	// selects the key as with git tag -u, and SigningFormat sets gpg.format
+	// (openpgp, x509 or ssh); both default to git's own configuration.
+	Sign          bool   `json:"sign"`
+	SigningKey    string `json:"signingKey"`
+	SigningFormat string `json:"signingFormat"`
+	// Format is a Go text/template naming release tags, "v{{.Version}}" by
+	// default. It receives .Version and .Package, so repositories holding
+	// several components can use "{{.Package}}/v{{.Version}}". Tags that
+	// don't match it are ignored when looking for releases.
+	Format  string `json:"format"`
+	Package string `json:"package"`
+}
+
+type ChangelogConfig struct {
+	// RepositoryURL is the web URL used for compare links. When empty it is
+	// derived from the origin remote.
+	RepositoryURL string `json:"repositoryURL"`
+	// Host selects the built-in link templates: github, gitlab, bitbucket
+	// or gitea. Detected from the repository URL when empty.
+	Host string `json:"host"`
+	// CompareURL and TagURL override the host's link templates.
+	CompareURL string `json:"compareURL"`
+	TagURL     string `json:"tagURL"`
+	// Preset names a built-in entry format; HeadingTemplate,
+	// SectionTemplate and EntryTemplate are Go text/templates that override
+	// parts of it.
+	Preset          string `json:"preset"`
+	HeadingTemplate string `json:"headingTemplate"`
+	SectionTemplate string `json:"sectionTemplate"`
+	EntryTemplate   string `json:"entryTemplate"`
+	// DateLayout is the Go time layout of release dates, "2006-01-02" by
+	// default. Dates are always in UTC.
+	DateLayout string `json:"dateLayout"`
+	// WrapWidth wraps long descriptions at this column; 0 keeps their
+	// line breaks.
+	WrapWidth int `json:"wrapWidth"`
+	// FromCommits pre-fills the TUI with entries generated from the
+	// commits since the last release tag.
+	FromCommits bool          `json:"fromCommits"`
+	Commits     CommitsConfig `json:"commits"`
+	// RefURLs maps "#" or a tracker key such as "PROJ" to a URL template
+	// for references in entries, e.g. "https://jira.example.com/browse/{{.ID}}".
+	// Issue numbers are linked through the repository host by default.
+	RefURLs map[string]string `json:"refURLs"`
+	// Contributors adds a Contributors section to each release, crediting
+	// the authors of its commits and marking first-time contributors.
+	Contributors bool `json:"contributors"`
+}
+
+type CommitsConfig struct {
+	// ExcludeTypes lists Conventional Commit types that never become
+	// entries.
+	ExcludeTypes  []string `json:"excludeTypes"`
+	IncludeMerges bool     `json:"includeMerges"`
 }
 
 func Load() (*Config, error) {
@@ -16,8 +138,50 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	return &Config{
-		VersionFile:   filepath.Join(wd, "VERSION.md"),
-		ChangelogFile: filepath.Join(wd, "CHANGELOG.md"),
-	}, nil
+	cfg := &Config{
+		VersionFile:   "VERSION.md",
+		ChangelogFile: "CHANGELOG.md",
+		Push:          PushConfig{Remote: "origin"},
+		Preflight: PreflightConfig{
+			Branches: []string{"main", "master", "release/*"},
+		},
+		ReleaseCommit: ReleaseCommitConfig{
+			Trailers: []TrailerConfig{{Token: "Release-Version", Value: "{{.Version}}"}},
+		},
+		Changelog: ChangelogConfig{
+			Commits: CommitsConfig{
+				ExcludeTypes: []string{"chore", "ci", "build", "test", "style"},
+			},
+		},
+	}
+
+	data, err := os.ReadFile(filepath.Join(wd, FileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", FileName, err)
+	}
+	if err == nil {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+		}
+	}
+
+	cfg.VersionFile = resolve(wd, cfg.VersionFile)
+	cfg.ChangelogFile = resolve(wd, cfg.ChangelogFile)
+	if cfg.UpgradingFile != "" {
+		cfg.UpgradingFile = resolve(wd, cfg.UpgradingFile)
+	}
+	for i, path := range cfg.ReleaseFiles {
+		cfg.ReleaseFiles[i] = resolve(wd, path)
+	}
+
+	return cfg, nil
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
 }
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -28,3 +30,94 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoad_File(t *testing.T) {
+	dir := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "overrides are applied",
+			content: `{
+				"changelogFile": "docs/CHANGES.md",
+				"upgradingFile": "UPGRADING.md",
+				"releaseFiles": ["package.json", "/abs/docs/version.txt"],
+				"changelog": {"repositoryURL": "https://gitlab.com/org/repo", "host": "gitlab", "contributors": true}
+			}`,
+			check: func(t *testing.T, cfg *Config) {
+				if want := filepath.Join(dir, "docs", "CHANGES.md"); cfg.ChangelogFile != want {
+					t.Errorf("ChangelogFile = %v, want %v", cfg.ChangelogFile, want)
+				}
+				if want := filepath.Join(dir, "UPGRADING.md"); cfg.UpgradingFile != want {
+					t.Errorf("UpgradingFile = %v, want %v", cfg.UpgradingFile, want)
+				}
+				if want := []string{filepath.Join(dir, "package.json"), "/abs/docs/version.txt"}; !reflect.DeepEqual(cfg.ReleaseFiles, want) {
+					t.Errorf("ReleaseFiles = %v, want %v", cfg.ReleaseFiles, want)
+				}
+				if want := filepath.Join(dir, "VERSION.md"); cfg.VersionFile != want {
+					t.Errorf("VersionFile = %v, want %v", cfg.VersionFile, want)
+				}
+				if cfg.Changelog.Host != "gitlab" || cfg.Changelog.RepositoryURL != "https://gitlab.com/org/repo" || !cfg.Changelog.Contributors {
+					t.Errorf("Changelog = %+v", cfg.Changelog)
+				}
+			},
+		},
+		{
+			name:    "release commit trailers replace the default",
+			content: `{"releaseCommit": {"trailers": [{"token": "Co-authored-by", "value": "Jane <jane@example.com>"}], "signOff": true}}`,
+			check: func(t *testing.T, cfg *Config) {
+				want := []TrailerConfig{{Token: "Co-authored-by", Value: "Jane <jane@example.com>"}}
+				if !reflect.DeepEqual(cfg.ReleaseCommit.Trailers, want) || !cfg.ReleaseCommit.SignOff {
+					t.Errorf("ReleaseCommit = %+v", cfg.ReleaseCommit)
+				}
+			},
+		},
+		{
+			name:    "tag format",
+			content: `{"tag": {"format": "{{.Package}}/v{{.Version}}", "package": "api"}}`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Tag.Format != "{{.Package}}/v{{.Version}}" || cfg.Tag.Package != "api" {
+					t.Errorf("Tag = %+v", cfg.Tag)
+				}
+			},
+		},
+		{
+			name:    "unknown fields are rejected",
+			content: `{"changelogfiel": "x"}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed json",
+			content: `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(dir, FileName), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package conventional
+
+import (
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// bumpRank orders bump types from smallest to largest.
+var bumpRank = map[version.Type]int{version.Patch: 1, version.Minor: 2, version.Major: 3}
+
+// Bump returns the bump c calls for on top of cur: major for breaking
+// changes, minor for feat and patch for fix and perf. Other types call for
+// none and return "". Before 1.0.0 breaking changes bump the minor version
+// instead, since the public API isn't considered stable yet.
+func (c *Commit) Bump(cur *version.Version) version.Type {
+	switch {
+	case c.Breaking && cur.Major == 0:
+		return version.Minor
+	case c.Breaking:
+		return version.Major
+	case c.Type == "feat":
+		return version.Minor
+	case c.Type == "fix" || c.Type == "perf":
+		return version.Patch
+	}
+	return ""
+}
+
+// Recommend returns the largest bump the commit messages call for on top of
+// cur, together with the headers of the commits that call for it. It
+// returns "" when no commit calls for a release.
+func Recommend(cur *version.Version, messages []string) (version.Type, []string) {
+	var (
+		best    version.Type
+		drivers []string
+	)
+	for _, msg := range messages {
+		c, err := Parse(msg)
+		if err != nil {
+			continue
+		}
+		t := c.Bump(cur)
+		if t == "" || bumpRank[t] < bumpRank[best] {
+			continue
+		}
+		if bumpRank[t] > bumpRank[best] {
+			best, drivers = t, nil
+		}
+		header, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+		drivers = append(drivers, strings.TrimSpace(header))
+	}
+	return best, drivers
+}
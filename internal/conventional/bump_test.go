@@ -0,0 +1,74 @@
+package conventional
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestRecommend(t *testing.T) {
+	stable := &version.Version{Major: 1, Minor: 2}
+	initial := &version.Version{Minor: 3}
+
+	tests := []struct {
+		name        string
+		cur         *version.Version
+		messages    []string
+		want        version.Type
+		wantDrivers []string
+	}{
+		{
+			name:        "fixes and perf",
+			cur:         stable,
+			messages:    []string{"fix: a", "docs: b", "perf(io): c"},
+			want:        version.Patch,
+			wantDrivers: []string{"fix: a", "perf(io): c"},
+		},
+		{
+			name:        "feature wins over fixes",
+			cur:         stable,
+			messages:    []string{"fix: a", "feat: b\n\nbody", "Merge branch 'x'"},
+			want:        version.Minor,
+			wantDrivers: []string{"feat: b"},
+		},
+		{
+			name:        "breaking marker",
+			cur:         stable,
+			messages:    []string{"feat: a", "refactor!: drop v1 api"},
+			want:        version.Major,
+			wantDrivers: []string{"refactor!: drop v1 api"},
+		},
+		{
+			name:        "breaking footer",
+			cur:         stable,
+			messages:    []string{"fix: a\n\nBREAKING CHANGE: config moved"},
+			want:        version.Major,
+			wantDrivers: []string{"fix: a"},
+		},
+		{
+			name:        "breaking before 1.0.0 bumps minor",
+			cur:         initial,
+			messages:    []string{"fix: a", "feat!: b"},
+			want:        version.Minor,
+			wantDrivers: []string{"feat!: b"},
+		},
+		{
+			name:     "nothing to release",
+			cur:      stable,
+			messages: []string{"docs: a", "chore: b", "not conventional"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, drivers := Recommend(tt.cur, tt.messages)
+			if got != tt.want {
+				t.Errorf("Recommend() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(drivers, tt.wantDrivers) {
+				t.Errorf("Recommend() drivers = %q, want %q", drivers, tt.wantDrivers)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package conventional parses commit messages written in the Conventional
+// Commits format (https://www.conventionalcommits.org).
+package conventional
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrNotConventional = errors.New("not a conventional commit")
+
+type Footer struct {
+	Token string
+	Value string
+}
+
+type Commit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Body        string
+	Footers     []Footer
+	// BreakingNote is the text of a BREAKING CHANGE footer, if any.
+	BreakingNote string
+}
+
+var (
+	headerPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()\r\n]+)\))?(!)?: (\S.*)$`)
+	footerPattern = regexp.MustCompile(`^([A-Za-z-]+|BREAKING CHANGE)(?:: | #)(.*)$`)
+)
+
+// Parse parses a full commit message. The header must follow the
+// specification; anything else returns ErrNotConventional.
+func Parse(message string) (*Commit, error) {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	header, rest, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	m := headerPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotConventional, header)
+	}
+
+	c := &Commit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: strings.TrimSpace(m[4]),
+	}
+
+	body, footers := splitFooters(strings.TrimSpace(rest))
+	c.Body = body
+	c.Footers = footers
+	for _, f := range footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			c.Breaking = true
+			c.BreakingNote = f.Value
+		}
+	}
+
+	return c, nil
+}
+
+// splitFooters separates the trailing footer paragraph from the body.
+// Footer values may continue on following lines.
+func splitFooters(text string) (string, []Footer) {
+	if text == "" {
+		return "", nil
+	}
+
+	paragraphs := strings.Split(text, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	lines := strings.Split(last, "\n")
+	if !footerPattern.MatchString(lines[0]) {
+		return text, nil
+	}
+
+	var footers []Footer
+	for _, line := range lines {
+		if m := footerPattern.FindStringSubmatch(line); m != nil {
+			footers = append(footers, Footer{Token: m[1], Value: strings.TrimSpace(m[2])})
+			continue
+		}
+		f := &footers[len(footers)-1]
+		f.Value = strings.TrimSpace(f.Value + "\n" + line)
+	}
+
+	body := strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+	return body, footers
+}
@@ -0,0 +1,84 @@
+package conventional
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    *Commit
+		wantErr error
+	}{
+		{
+			name:    "type and description",
+			message: "feat: add export",
+			want:    &Commit{Type: "feat", Description: "add export"},
+		},
+		{
+			name:    "scope and breaking marker",
+			message: "fix(loader)!: drop legacy paths",
+			want:    &Commit{Type: "fix", Scope: "loader", Breaking: true, Description: "drop legacy paths"},
+		},
+		{
+			name: "body and footers",
+			message: `feat(api): add v2 endpoints
+
+The old endpoints are kept for now.
+
+Second paragraph.
+
+Refs: #123
+BREAKING CHANGE: clients must send
+the version header
+Reviewed-by: Z`,
+			want: &Commit{
+				Type:        "feat",
+				Scope:       "api",
+				Breaking:    true,
+				Description: "add v2 endpoints",
+				Body:        "The old endpoints are kept for now.\n\nSecond paragraph.",
+				Footers: []Footer{
+					{Token: "Refs", Value: "#123"},
+					{Token: "BREAKING CHANGE", Value: "clients must send\nthe version header"},
+					{Token: "Reviewed-by", Value: "Z"},
+				},
+				BreakingNote: "clients must send\nthe version header",
+			},
+		},
+		{
+			name:    "hash footer and uppercase type",
+			message: "Fix: typo\n\nCloses #42",
+			want: &Commit{
+				Type:        "fix",
+				Description: "typo",
+				Footers:     []Footer{{Token: "Closes", Value: "42"}},
+			},
+		},
+		{
+			name:    "plain message",
+			message: "Update readme",
+			wantErr: ErrNotConventional,
+		},
+		{
+			name:    "missing space after colon",
+			message: "feat:add",
+			wantErr: ErrNotConventional,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
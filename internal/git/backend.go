@@ -0,0 +1,121 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// Backend names accepted by NewBackend.
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go-git"
+)
+
+var ErrUnknownBackend = errors.New("unknown git backend")
+
+// Backend runs the git operations GitService is built on. Paths may be
+// absolute or relative to the working directory, which can be anywhere
+// inside the repository. Backends report failures as they get them;
+// GitService wraps them in its own errors.
+type Backend interface {
+	// Add stages paths.
+	Add(ctx context.Context, paths []string) error
+	// Commit records the index with message, adding a Signed-off-by
+	// trailer for the committer when signOff is set.
+	Commit(ctx context.Context, message string, signOff bool) error
+	// Staged lists the staged paths other than exclude, relative to the
+	// repository root.
+	Staged(ctx context.Context, exclude []string) ([]string, error)
+	// Unstaged lists the tracked paths with changes not yet staged, other
+	// than exclude, relative to the repository root.
+	Unstaged(ctx context.Context, exclude []string) ([]string, error)
+	// Changes lists the tracked paths with staged or unstaged changes,
+	// other than exclude, relative to the repository root.
+	Changes(ctx context.Context, exclude []string) ([]string, error)
+	// CreateTag tags HEAD. An empty message makes a lightweight tag unless
+	// sign is set.
+	CreateTag(ctx context.Context, name, message string, sign *Signing) error
+	// VerifyTag checks the signature of tag and returns the report on it.
+	VerifyTag(ctx context.Context, name string, sign *Signing) (string, error)
+	// Push atomically sends HEAD to branch on remote together with tag.
+	// An empty branch means the current one and an empty tag pushes the
+	// commit alone.
+	Push(ctx context.Context, remote, branch, tag string) error
+	// PushRef sends the local ref, such as a notes ref, to the ref of the
+	// same name on remote.
+	PushRef(ctx context.Context, remote, ref string) error
+	RemoteURL(ctx context.Context, name string) (string, error)
+	// Tags lists the tags reachable from HEAD.
+	Tags(ctx context.Context) ([]string, error)
+	// Log returns the commits reachable from HEAD but not from since,
+	// newest first.
+	Log(ctx context.Context, since string) ([]Commit, error)
+	// Authors returns the author of each commit reachable from until but
+	// not from since, newest first, with .mailmap applied. An empty since
+	// covers the whole history.
+	Authors(ctx context.Context, since, until string) ([]Author, error)
+	// DiffStat returns the lines changed in each file between the commits
+	// from and to point at, by path. Renames count as a removal and an
+	// addition. An empty from compares with an empty tree.
+	DiffStat(ctx context.Context, from, to string) ([]FileStat, error)
+	// Config returns a configuration value, or "" when it is unset.
+	Config(ctx context.Context, key string) (string, error)
+	// Branch returns the current branch, or "" when HEAD is detached.
+	Branch(ctx context.Context) (string, error)
+	// Behind returns the upstream of the current branch, "" when there is
+	// none, and how many of its commits HEAD lacks.
+	Behind(ctx context.Context) (string, int, error)
+	TagExists(ctx context.Context, name string) (bool, error)
+	// DeleteTag deletes a local tag.
+	DeleteTag(ctx context.Context, name string) error
+	// Resolve returns the hash of the commit rev points at.
+	Resolve(ctx context.Context, rev string) (string, error)
+	// IsAncestor reports whether the commit ancestor names is reachable
+	// from the one rev names. Either may be an annotated tag.
+	IsAncestor(ctx context.Context, ancestor, rev string) (bool, error)
+	// ResetSoft moves the current branch to rev, leaving the index and
+	// working tree as they are.
+	ResetSoft(ctx context.Context, rev string) error
+	// RemoteRefs lists the branches and tags on remote with the hashes
+	// they point at, as the remote reports them.
+	RemoteRefs(ctx context.Context, remote string) (map[string]string, error)
+	// AddNote attaches note to the commit rev points at under the notes
+	// ref, replacing the note already there.
+	AddNote(ctx context.Context, ref, rev, note string) error
+	// RemoveNote removes the note on the commit rev names under the notes
+	// ref, if there is one.
+	RemoveNote(ctx context.Context, ref, rev string) error
+	// Notes returns the notes under ref by the hash of the commit they are
+	// attached to.
+	Notes(ctx context.Context, ref string) (map[string]string, error)
+	// CreateBranch creates branch name at the commit rev points at,
+	// failing when the branch already exists. HEAD is left alone.
+	CreateBranch(ctx context.Context, name, rev string) error
+	// Identity returns the configured committer as "Name <email>".
+	Identity(ctx context.Context) (string, error)
+	// HooksDir returns the absolute path of the hooks directory, honoring
+	// core.hooksPath.
+	HooksDir(ctx context.Context) (string, error)
+	// FileAt returns the content of path in the commit rev points at.
+	FileAt(ctx context.Context, rev, path string) ([]byte, error)
+}
+
+// NewBackend returns the backend called name. An empty name picks the exec
+// backend when a git binary is installed and go-git otherwise.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "":
+		if _, err := exec.LookPath("git"); err != nil {
+			return NewGoGitBackend(), nil
+		}
+		return NewExecBackend(), nil
+	case BackendExec:
+		return NewExecBackend(), nil
+	case BackendGoGit:
+		return NewGoGitBackend(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q, want %s or %s", ErrUnknownBackend, name, BackendExec, BackendGoGit)
+	}
+}
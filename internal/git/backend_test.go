@@ -0,0 +1,702 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestExecBackend(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	testBackend(t, NewExecBackend())
+}
+
+func TestGoGitBackend(t *testing.T) {
+	testBackend(t, NewGoGitBackend())
+}
+
+func TestNewBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Backend
+		wantErr error
+	}{
+		{name: "exec", want: &ExecBackend{}},
+		{name: "go-git", want: &GoGitBackend{}},
+		{name: "libgit2", wantErr: ErrUnknownBackend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBackend(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewBackend() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewBackend() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+// testRepo is a repository set up with go-git, so that the suite needs no
+// git binary, and made the working directory.
+type testRepo struct {
+	dir  string
+	repo *gogit.Repository
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	// Keep the user's configuration out of the tests.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	repo, err := gogit.PlainInitWithOptions(dir, &gogit.PlainInitOptions{
+		InitOptions: gogit.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	r := &testRepo{dir: dir, repo: repo}
+	r.setConfig(t, func(cfg *gitconfig.Config) {
+		cfg.User.Name = "Test User"
+		cfg.User.Email = "test@example.com"
+	})
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+	return r
+}
+
+func (r *testRepo) setConfig(t *testing.T, edit func(*gitconfig.Config)) {
+	t.Helper()
+	cfg, err := r.repo.Config()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	edit(cfg)
+	if err := r.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func (r *testRepo) write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(r.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// commit commits name with the backend under test and returns the new HEAD.
+func (r *testRepo) commit(t *testing.T, b Backend, name, message string) plumbing.Hash {
+	t.Helper()
+	ctx := context.Background()
+	if err := b.Add(ctx, []string{r.write(t, name, message)}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := b.Commit(ctx, message, false); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	return r.head(t)
+}
+
+func (r *testRepo) head(t *testing.T) plumbing.Hash {
+	t.Helper()
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	return head.Hash()
+}
+
+func (r *testRepo) setRef(t *testing.T, name plumbing.ReferenceName, hash plumbing.Hash) {
+	t.Helper()
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(name, hash)); err != nil {
+		t.Fatalf("Failed to set %s: %v", name, err)
+	}
+}
+
+// testBackend is the conformance suite every Backend passes.
+func testBackend(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	t.Run("commit from a subdirectory", func(t *testing.T) {
+		r := newTestRepo(t)
+		r.commit(t, b, "README.md", "initial commit")
+
+		files := []string{r.write(t, "VERSION.md", "1.0.0"), r.write(t, "CHANGELOG.md", "# Changelog")}
+		if err := os.Mkdir(filepath.Join(r.dir, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(filepath.Join(r.dir, "sub")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Add(ctx, []string{files[0], "../CHANGELOG.md"}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := b.Commit(ctx, "Release 1.0.0\n\nRelease-Version: 1.0.0\n", true); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+
+		commit, err := r.repo.CommitObject(r.head(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "Release 1.0.0\n\nRelease-Version: 1.0.0\nSigned-off-by: Test User <test@example.com>\n"
+		if commit.Message != want {
+			t.Errorf("message = %q, want %q", commit.Message, want)
+		}
+		for _, name := range []string{"VERSION.md", "CHANGELOG.md"} {
+			if _, err := commit.File(name); err != nil {
+				t.Errorf("%s not committed: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("empty commit message", func(t *testing.T) {
+		r := newTestRepo(t)
+		if err := b.Add(ctx, []string{r.write(t, "a.go", "package a")}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := b.Commit(ctx, "", false); err == nil {
+			t.Error("Commit() with an empty message succeeded")
+		}
+	})
+
+	t.Run("staged and changed files", func(t *testing.T) {
+		r := newTestRepo(t)
+		r.write(t, "b.go", "package b")
+		r.commit(t, b, "a.go", "package a")
+		if err := b.Add(ctx, []string{r.write(t, "c.go", "package c")}); err != nil {
+			t.Fatal(err)
+		}
+		r.write(t, "b.go", "package b // changed")
+		if err := b.Add(ctx, []string{"b.go"}); err != nil {
+			t.Fatal(err)
+		}
+		r.write(t, "a.go", "package a // changed")
+		// Staged and changed again.
+		r.write(t, "c.go", "package c // changed")
+		r.write(t, "untracked.go", "package u")
+
+		tests := []struct {
+			name    string
+			list    func(context.Context, []string) ([]string, error)
+			exclude []string
+			want    []string
+		}{
+			{name: "staged", list: b.Staged, want: []string{"b.go", "c.go"}},
+			{name: "staged except", list: b.Staged, exclude: []string{filepath.Join(r.dir, "c.go")}, want: []string{"b.go"}},
+			{name: "unstaged", list: b.Unstaged, want: []string{"a.go", "c.go"}},
+			{name: "unstaged except", list: b.Unstaged, exclude: []string{"a.go"}, want: []string{"c.go"}},
+			{name: "changes", list: b.Changes, want: []string{"a.go", "b.go", "c.go"}},
+			{name: "changes except", list: b.Changes, exclude: []string{"a.go", "b.go"}, want: []string{"c.go"}},
+		}
+		for _, tt := range tests {
+			got, err := tt.list(ctx, tt.exclude)
+			if err != nil {
+				t.Fatalf("%s: error = %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("tags and log", func(t *testing.T) {
+		r := newTestRepo(t)
+		first := r.commit(t, b, "a.go", "feat: first")
+		if err := b.CreateTag(ctx, "v1.0.0", "", nil); err != nil {
+			t.Fatalf("CreateTag() error = %v", err)
+		}
+		second := r.commit(t, b, "b.go", "fix: second")
+		if err := b.CreateTag(ctx, "v1.0.1", "Release v1.0.1\n\n### Fixed\n- second\n", nil); err != nil {
+			t.Fatalf("CreateTag() annotated error = %v", err)
+		}
+		if err := b.CreateTag(ctx, "v1.0.1", "", nil); err == nil {
+			t.Error("CreateTag() of an existing tag succeeded")
+		}
+
+		ref, err := r.repo.Tag("v1.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := r.repo.TagObject(ref.Hash())
+		if err != nil {
+			t.Fatalf("v1.0.1 is not annotated: %v", err)
+		}
+		if want := "Release v1.0.1\n\n### Fixed\n- second\n"; tag.Message != want || tag.Target != second {
+			t.Errorf("tag = %q on %s, want %q on %s", tag.Message, tag.Target, want, second)
+		}
+
+		third := r.commit(t, b, "c.go", "docs: third")
+		// A tag that isn't reachable from HEAD.
+		r.setRef(t, plumbing.NewTagReferenceName("v0.9.0"), plumbing.NewHash("1111111111111111111111111111111111111111"))
+
+		tags, err := b.Tags(ctx)
+		if err != nil {
+			t.Fatalf("Tags() error = %v", err)
+		}
+		if want := []string{"v1.0.0", "v1.0.1"}; !reflect.DeepEqual(tags, want) {
+			t.Errorf("Tags() = %q, want %q", tags, want)
+		}
+		for name, want := range map[string]bool{"v1.0.0": true, "v1.0.1": true, "v2.0.0": false} {
+			if got, err := b.TagExists(ctx, name); err != nil || got != want {
+				t.Errorf("TagExists(%s) = %v, %v, want %v", name, got, err, want)
+			}
+		}
+
+		commits, err := b.Log(ctx, "v1.0.0")
+		if err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+		want := []Commit{
+			{Hash: third.String(), Author: "Test User", Email: "test@example.com", Message: "docs: third"},
+			{Hash: second.String(), Author: "Test User", Email: "test@example.com", Message: "fix: second"},
+		}
+		if !reflect.DeepEqual(commits, want) {
+			t.Errorf("Log(v1.0.0) = %+v, want %+v", commits, want)
+		}
+		if commits, err := b.Log(ctx, ""); err != nil || len(commits) != 3 || commits[2].Hash != first.String() {
+			t.Errorf("Log() = %+v, %v, want 3 commits ending with %s", commits, err, first)
+		}
+	})
+
+	t.Run("config and identity", func(t *testing.T) {
+		r := newTestRepo(t)
+		if ident, err := b.Identity(ctx); err != nil || ident != "Test User <test@example.com>" {
+			t.Errorf("Identity() = %q, %v", ident, err)
+		}
+
+		r.setConfig(t, func(cfg *gitconfig.Config) {
+			cfg.Raw.Section("github").SetOption("user", "octocat")
+			cfg.Raw.Section("semver").Subsection("release").SetOption("remote", "upstream")
+		})
+		for key, want := range map[string]string{
+			"github.user":           "octocat",
+			"semver.release.remote": "upstream",
+			"user.name":             "Test User",
+			"gitlab.user":           "",
+		} {
+			if got, err := b.Config(ctx, key); err != nil || got != want {
+				t.Errorf("Config(%s) = %q, %v, want %q", key, got, err, want)
+			}
+		}
+
+		r.setConfig(t, func(cfg *gitconfig.Config) {
+			cfg.User.Name, cfg.User.Email = "", ""
+			cfg.Raw.RemoveSection("user")
+		})
+		if ident, err := b.Identity(ctx); err == nil {
+			t.Errorf("Identity() without a configured user = %q", ident)
+		}
+	})
+
+	t.Run("branch and upstream", func(t *testing.T) {
+		r := newTestRepo(t)
+		first := r.commit(t, b, "a.go", "first")
+
+		if branch, err := b.Branch(ctx); err != nil || branch != "main" {
+			t.Errorf("Branch() = %q, %v, want main", branch, err)
+		}
+		if upstream, n, err := b.Behind(ctx); err != nil || upstream != "" || n != 0 {
+			t.Errorf("Behind() without upstream = %q, %d, %v", upstream, n, err)
+		}
+
+		// origin/main is two commits ahead of main.
+		r.commit(t, b, "b.go", "second")
+		ahead := r.commit(t, b, "c.go", "third")
+		r.setRef(t, plumbing.NewRemoteReferenceName("origin", "main"), ahead)
+		r.setRef(t, plumbing.NewBranchReferenceName("main"), first)
+		r.setConfig(t, func(cfg *gitconfig.Config) {
+			cfg.Remotes["origin"] = &gitconfig.RemoteConfig{
+				Name:  "origin",
+				URLs:  []string{"https://example.com/org/repo.git"},
+				Fetch: []gitconfig.RefSpec{"+refs/heads/*:refs/remotes/origin/*"},
+			}
+			cfg.Branches["main"] = &gitconfig.Branch{Name: "main", Remote: "origin", Merge: plumbing.NewBranchReferenceName("main")}
+		})
+		if upstream, n, err := b.Behind(ctx); err != nil || upstream != "origin/main" || n != 2 {
+			t.Errorf("Behind() = %q, %d, %v, want origin/main, 2", upstream, n, err)
+		}
+		if url, err := b.RemoteURL(ctx, "origin"); err != nil || url != "https://example.com/org/repo.git" {
+			t.Errorf("RemoteURL() = %q, %v", url, err)
+		}
+		if _, err := b.RemoteURL(ctx, "upstream"); err == nil {
+			t.Error("RemoteURL() of a missing remote succeeded")
+		}
+
+		if err := r.repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, first)); err != nil {
+			t.Fatal(err)
+		}
+		if branch, err := b.Branch(ctx); err != nil || branch != "" {
+			t.Errorf("Branch() with detached HEAD = %q, %v", branch, err)
+		}
+	})
+
+	t.Run("create branch", func(t *testing.T) {
+		r := newTestRepo(t)
+		first := r.commit(t, b, "a.go", "first")
+		if err := b.CreateTag(ctx, "v1.4.0", "Release v1.4.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		r.commit(t, b, "b.go", "second")
+
+		if err := b.CreateBranch(ctx, "release/1.4", "v1.4.0"); err != nil {
+			t.Fatalf("CreateBranch() error = %v", err)
+		}
+		ref, err := r.repo.Reference(plumbing.NewBranchReferenceName("release/1.4"), false)
+		if err != nil || ref.Hash() != first {
+			t.Errorf("release/1.4 = %v, %v, want %s", ref, err, first)
+		}
+		if branch, err := b.Branch(ctx); err != nil || branch != "main" {
+			t.Errorf("Branch() after CreateBranch() = %q, %v, want main", branch, err)
+		}
+		if err := b.CreateBranch(ctx, "release/1.4", "HEAD"); err == nil {
+			t.Error("CreateBranch() of an existing branch succeeded")
+		}
+		if err := b.CreateBranch(ctx, "release/9.9", "v9.9.0"); err == nil {
+			t.Error("CreateBranch() from a missing tag succeeded")
+		}
+	})
+
+	t.Run("reset and delete tag", func(t *testing.T) {
+		r := newTestRepo(t)
+		first := r.commit(t, b, "VERSION.md", "1.0.0")
+		second := r.commit(t, b, "VERSION.md", "1.1.0")
+		if err := b.CreateTag(ctx, "v1.1.0", "Release v1.1.0", nil); err != nil {
+			t.Fatal(err)
+		}
+
+		for rev, want := range map[string]plumbing.Hash{"HEAD": second, "HEAD^": first, "v1.1.0": second, first.String(): first} {
+			if got, err := b.Resolve(ctx, rev); err != nil || got != want.String() {
+				t.Errorf("Resolve(%s) = %s, %v, want %s", rev, got, err, want)
+			}
+		}
+		if _, err := b.Resolve(ctx, "v9.9.9"); err == nil {
+			t.Error("Resolve() of a missing tag succeeded")
+		}
+
+		for _, tt := range []struct {
+			ancestor, rev string
+			want          bool
+		}{
+			{first.String(), second.String(), true},
+			{second.String(), first.String(), false},
+			{second.String(), "v1.1.0", true},
+			{"HEAD", "HEAD", true},
+		} {
+			if got, err := b.IsAncestor(ctx, tt.ancestor, tt.rev); err != nil || got != tt.want {
+				t.Errorf("IsAncestor(%s, %s) = %v, %v, want %v", tt.ancestor, tt.rev, got, err, tt.want)
+			}
+		}
+		if _, err := b.IsAncestor(ctx, "HEAD", strings.Repeat("a", 40)); err == nil {
+			t.Error("IsAncestor() of an unknown commit succeeded")
+		}
+
+		if err := b.DeleteTag(ctx, "v1.1.0"); err != nil {
+			t.Fatalf("DeleteTag() error = %v", err)
+		}
+		if ok, err := b.TagExists(ctx, "v1.1.0"); err != nil || ok {
+			t.Errorf("TagExists() after DeleteTag() = %v, %v", ok, err)
+		}
+		if err := b.DeleteTag(ctx, "v1.1.0"); err == nil {
+			t.Error("DeleteTag() of a missing tag succeeded")
+		}
+
+		if err := b.ResetSoft(ctx, "HEAD^"); err != nil {
+			t.Fatalf("ResetSoft() error = %v", err)
+		}
+		if head := r.head(t); head != first {
+			t.Errorf("HEAD = %s, want %s", head, first)
+		}
+		if branch, err := b.Branch(ctx); err != nil || branch != "main" {
+			t.Errorf("Branch() after ResetSoft() = %q, %v, want main", branch, err)
+		}
+		if staged, err := b.Staged(ctx, nil); err != nil || !reflect.DeepEqual(staged, []string{"VERSION.md"}) {
+			t.Errorf("Staged() after ResetSoft() = %q, %v, want the reset change", staged, err)
+		}
+
+		// Adding a removed file stages its removal.
+		if err := os.Remove(filepath.Join(r.dir, "VERSION.md")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Add(ctx, []string{"VERSION.md"}); err != nil {
+			t.Fatalf("Add() of a removed file error = %v", err)
+		}
+		if err := b.Commit(ctx, "remove", false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.FileAt(ctx, "HEAD", "VERSION.md"); err == nil {
+			t.Error("VERSION.md is still committed")
+		}
+	})
+
+	t.Run("notes", func(t *testing.T) {
+		const ref = "refs/notes/test"
+		r := newTestRepo(t)
+		first := r.commit(t, b, "VERSION.md", "1.0.0")
+		if err := b.CreateTag(ctx, "v1.0.0", "Release v1.0.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		second := r.commit(t, b, "VERSION.md", "1.1.0")
+
+		if notes, err := b.Notes(ctx, ref); err != nil || len(notes) != 0 {
+			t.Errorf("Notes() before any note = %q, %v", notes, err)
+		}
+		for _, n := range []struct{ rev, note string }{
+			{"v1.0.0", "{\n  \"version\": \"1.0.0\"\n}\n"},
+			{"HEAD", "stale"},
+			{"HEAD", "{\"version\": \"1.1.0\"}"},
+		} {
+			if err := b.AddNote(ctx, ref, n.rev, n.note); err != nil {
+				t.Fatalf("AddNote(%s) error = %v", n.rev, err)
+			}
+		}
+		want := map[string]string{
+			first.String():  "{\n  \"version\": \"1.0.0\"\n}\n",
+			second.String(): "{\"version\": \"1.1.0\"}\n",
+		}
+		if notes, err := b.Notes(ctx, ref); err != nil || !reflect.DeepEqual(notes, want) {
+			t.Errorf("Notes() = %q, %v, want %q", notes, err, want)
+		}
+		if err := b.AddNote(ctx, ref, "v9.9.9", "missing"); err == nil {
+			t.Error("AddNote() to a missing revision succeeded")
+		}
+
+		// Notes must be readable by git itself.
+		if _, err := exec.LookPath("git"); err == nil {
+			out, err := exec.Command("git", "notes", "--ref="+ref, "show", first.String()).Output()
+			if err != nil || string(out) != want[first.String()] {
+				t.Errorf("git notes show = %q, %v, want %q", out, err, want[first.String()])
+			}
+		}
+
+		for range 2 {
+			if err := b.RemoveNote(ctx, ref, second.String()); err != nil {
+				t.Fatalf("RemoveNote() error = %v", err)
+			}
+		}
+		delete(want, second.String())
+		if notes, err := b.Notes(ctx, ref); err != nil || !reflect.DeepEqual(notes, want) {
+			t.Errorf("Notes() after RemoveNote() = %q, %v, want %q", notes, err, want)
+		}
+	})
+
+	t.Run("authors and diff stat", func(t *testing.T) {
+		r := newTestRepo(t)
+		commit := func(files map[string]string) {
+			t.Helper()
+			var paths []string
+			for name, content := range files {
+				paths = append(paths, r.write(t, name, content))
+			}
+			if err := b.Add(ctx, paths); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Commit(ctx, "change", false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		as := func(name, email string) {
+			r.setConfig(t, func(cfg *gitconfig.Config) {
+				cfg.User.Name = name
+				cfg.User.Email = email
+			})
+		}
+
+		commit(map[string]string{"a.txt": "one\n"})
+		if err := b.CreateTag(ctx, "v1.0.0", "Release v1.0.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		as("Old Name", "OLD@example.com")
+		commit(map[string]string{"a.txt": "one\ntwo\nthree\n", "b.bin": "\x00\x01"})
+		as("New Person", "new@example.com")
+		commit(map[string]string{"a.txt": "one\nthree\n", "c.txt": "x\ny"})
+		// The mailmap is read from the working tree, committed or not.
+		r.write(t, ".mailmap", "# Old commits\nTest User <test@example.com> Old Name <old@example.com>\n")
+
+		authors, err := b.Authors(ctx, "v1.0.0", "HEAD")
+		want := []Author{{"New Person", "new@example.com"}, {"Test User", "test@example.com"}}
+		if err != nil || !reflect.DeepEqual(authors, want) {
+			t.Errorf("Authors(v1.0.0, HEAD) = %v, %v, want %v", authors, err, want)
+		}
+		authors, err = b.Authors(ctx, "", "v1.0.0")
+		if want := []Author{{"Test User", "test@example.com"}}; err != nil || !reflect.DeepEqual(authors, want) {
+			t.Errorf("Authors(v1.0.0) = %v, %v, want %v", authors, err, want)
+		}
+
+		stats, err := b.DiffStat(ctx, "v1.0.0", "HEAD")
+		wantStats := []FileStat{
+			{Path: "a.txt", Insertions: 1},
+			{Path: "b.bin", Binary: true},
+			{Path: "c.txt", Insertions: 2},
+		}
+		if err != nil || !reflect.DeepEqual(stats, wantStats) {
+			t.Errorf("DiffStat(v1.0.0, HEAD) = %+v, %v, want %+v", stats, err, wantStats)
+		}
+		stats, err = b.DiffStat(ctx, "HEAD", "HEAD^")
+		wantStats = []FileStat{
+			{Path: "a.txt", Insertions: 1},
+			{Path: "c.txt", Deletions: 2},
+		}
+		if err != nil || !reflect.DeepEqual(stats, wantStats) {
+			t.Errorf("DiffStat(HEAD, HEAD^) = %+v, %v, want %+v", stats, err, wantStats)
+		}
+		stats, err = b.DiffStat(ctx, "", "v1.0.0")
+		if want := []FileStat{{Path: "a.txt", Insertions: 1}}; err != nil || !reflect.DeepEqual(stats, want) {
+			t.Errorf("DiffStat(v1.0.0) = %+v, %v, want %+v", stats, err, want)
+		}
+	})
+
+	t.Run("hooks directory", func(t *testing.T) {
+		r := newTestRepo(t)
+		if dir, err := b.HooksDir(ctx); err != nil || dir != filepath.Join(r.dir, ".git", "hooks") {
+			t.Errorf("HooksDir() = %q, %v", dir, err)
+		}
+		r.setConfig(t, func(cfg *gitconfig.Config) {
+			cfg.Raw.Section("core").SetOption("hooksPath", "githooks")
+		})
+		if dir, err := b.HooksDir(ctx); err != nil || dir != filepath.Join(r.dir, "githooks") {
+			t.Errorf("HooksDir() with core.hooksPath = %q, %v", dir, err)
+		}
+	})
+
+	t.Run("file at revision", func(t *testing.T) {
+		r := newTestRepo(t)
+		first := r.commit(t, b, "VERSION.md", "1.0.0")
+		if err := b.CreateTag(ctx, "v1.0.0", "Release v1.0.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		r.commit(t, b, "VERSION.md", "1.1.0")
+		ref, err := r.repo.Tag("v1.0.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			rev, path, want string
+		}{
+			{rev: "HEAD", path: "VERSION.md", want: "1.1.0"},
+			{rev: first.String(), path: filepath.Join(r.dir, "VERSION.md"), want: "1.0.0"},
+			// The tag object rather than the commit, as pre-push hooks get.
+			{rev: ref.Hash().String(), path: "VERSION.md", want: "1.0.0"},
+		}
+		for _, tt := range tests {
+			if got, err := b.FileAt(ctx, tt.rev, tt.path); err != nil || string(got) != tt.want {
+				t.Errorf("FileAt(%s, %s) = %q, %v, want %q", tt.rev, tt.path, got, err, tt.want)
+			}
+		}
+		if _, err := b.FileAt(ctx, "HEAD", "CHANGELOG.md"); err == nil {
+			t.Error("FileAt() of a missing file succeeded")
+		}
+	})
+
+	t.Run("push", func(t *testing.T) {
+		// Both backends need git-receive-pack for remotes on disk.
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+		r := newTestRepo(t)
+		remoteDir := t.TempDir()
+		remote, err := gogit.PlainInit(remoteDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.setConfig(t, func(cfg *gitconfig.Config) {
+			cfg.Remotes["origin"] = &gitconfig.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}
+		})
+
+		head := r.commit(t, b, "a.go", "first")
+		if err := b.CreateTag(ctx, "v1.0.0", "Release v1.0.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Push(ctx, "origin", "", "v1.0.0"); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		if err := b.Push(ctx, "origin", "release/1.0", ""); err != nil {
+			t.Fatalf("Push() to another branch error = %v", err)
+		}
+
+		for _, name := range []plumbing.ReferenceName{"refs/heads/main", "refs/heads/release/1.0", "refs/tags/v1.0.0"} {
+			ref, err := remote.Reference(name, true)
+			if err != nil {
+				t.Errorf("remote lacks %s: %v", name, err)
+				continue
+			}
+			hash := ref.Hash()
+			if tag, err := remote.TagObject(hash); err == nil {
+				hash = tag.Target
+			}
+			if hash != head {
+				t.Errorf("remote %s = %s, want %s", name, hash, head)
+			}
+		}
+
+		refs, err := b.RemoteRefs(ctx, "origin")
+		if err != nil {
+			t.Fatalf("RemoteRefs() error = %v", err)
+		}
+		tagRef, err := r.repo.Tag("v1.0.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"refs/heads/main":        head.String(),
+			"refs/heads/release/1.0": head.String(),
+			"refs/tags/v1.0.0":       tagRef.Hash().String(),
+		}
+		if !reflect.DeepEqual(refs, want) {
+			t.Errorf("RemoteRefs() = %v, want %v", refs, want)
+		}
+		if _, err := b.RemoteRefs(ctx, "nowhere"); err == nil {
+			t.Error("RemoteRefs() of a missing remote succeeded")
+		}
+
+		if err := b.AddNote(ctx, "refs/notes/semver", "HEAD", "released"); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.PushRef(ctx, "origin", "refs/notes/semver"); err != nil {
+			t.Fatalf("PushRef() error = %v", err)
+		}
+		local, err := r.repo.Reference("refs/notes/semver", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref, err := remote.Reference("refs/notes/semver", true); err != nil || ref.Hash() != local.Hash() {
+			t.Errorf("remote notes = %v, %v, want %s", ref, err, local.Hash())
+		}
+		if err := b.PushRef(ctx, "origin", "refs/notes/semver"); err != nil {
+			t.Errorf("PushRef() when up to date error = %v", err)
+		}
+
+		if err := b.Push(ctx, "nowhere", "", ""); err == nil {
+			t.Error("Push() to a missing remote succeeded")
+		}
+	})
+}
@@ -0,0 +1,495 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CommandError reports a git command that failed, with what it printed to
+// standard error: hook output, a missing identity and the like.
+type CommandError struct {
+	Args []string
+	// ExitCode is -1 when git could not be run at all.
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("git %s: %v", e.command(), e.Err)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// command returns the git subcommand, skipping -c options before it.
+func (e *CommandError) command() string {
+	for i := 0; i < len(e.Args); i++ {
+		if e.Args[i] == "-c" {
+			i++
+			continue
+		}
+		return e.Args[i]
+	}
+	return ""
+}
+
+// ExecBackend runs the git binary.
+type ExecBackend struct{}
+
+func NewExecBackend() *ExecBackend {
+	return &ExecBackend{}
+}
+
+// run runs git with args, feeding it stdin, and returns its output. A
+// failure is a *CommandError.
+func (b *ExecBackend) run(ctx context.Context, stdin string, args ...string) (stdout, stderr string, err error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+	stdout, stderr = outBuf.String(), strings.TrimSpace(errBuf.String())
+	if err != nil {
+		cerr := &CommandError{Args: args, ExitCode: -1, Stderr: stderr, Err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cerr.ExitCode = exitErr.ExitCode()
+		}
+		return stdout, stderr, cerr
+	}
+	return stdout, stderr, nil
+}
+
+// exitCode returns the exit code of a failed command, or -1.
+func exitCode(err error) int {
+	var cerr *CommandError
+	if errors.As(err, &cerr) {
+		return cerr.ExitCode
+	}
+	return -1
+}
+
+func (b *ExecBackend) Add(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, _, err := b.run(ctx, "", append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
+func (b *ExecBackend) Commit(ctx context.Context, message string, signOff bool) error {
+	args := []string{"commit", "-m", message}
+	if signOff {
+		args = append(args, "--signoff")
+	}
+	_, _, err := b.run(ctx, "", args...)
+	return err
+}
+
+func (b *ExecBackend) Staged(ctx context.Context, exclude []string) ([]string, error) {
+	return b.diffNames(ctx, []string{"diff", "--cached", "--name-only", "-z"}, exclude)
+}
+
+func (b *ExecBackend) Unstaged(ctx context.Context, exclude []string) ([]string, error) {
+	return b.diffNames(ctx, []string{"diff", "--name-only", "-z"}, exclude)
+}
+
+func (b *ExecBackend) diffNames(ctx context.Context, args, exclude []string) ([]string, error) {
+	out, _, err := b.run(ctx, "", append(args, pathspecs(exclude)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func (b *ExecBackend) Changes(ctx context.Context, exclude []string) ([]string, error) {
+	args := append([]string{"status", "--porcelain=v1", "-z", "--untracked-files=no"}, pathspecs(exclude)...)
+	out, _, err := b.run(ctx, "", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	records := strings.Split(out, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 4 {
+			continue
+		}
+		changes = append(changes, record[3:])
+		// Renames and copies are followed by their original path.
+		if record[0] == 'R' || record[0] == 'C' {
+			i++
+		}
+	}
+	return changes, nil
+}
+
+// pathspecs matches the whole repository except exclude.
+func pathspecs(exclude []string) []string {
+	specs := []string{"--", ":/"}
+	for _, f := range exclude {
+		specs = append(specs, ":(exclude)"+f)
+	}
+	return specs
+}
+
+func (b *ExecBackend) CreateTag(ctx context.Context, name, message string, sign *Signing) error {
+	args := append(signingConfig(sign), "tag")
+	switch {
+	case sign != nil && sign.Key != "":
+		args = append(args, "-u", sign.Key)
+	case sign != nil:
+		args = append(args, "-s")
+	case message != "":
+		args = append(args, "-a")
+	}
+	if message != "" {
+		// Keep "### Added" and similar headings, which the default cleanup
+		// would strip as comments.
+		args = append(args, "--cleanup=whitespace", "-F", "-")
+	}
+	args = append(args, name)
+
+	_, _, err := b.run(ctx, message, args...)
+	return err
+}
+
+func (b *ExecBackend) VerifyTag(ctx context.Context, name string, sign *Signing) (string, error) {
+	// git prints the signature report to standard error.
+	_, report, err := b.run(ctx, "", append(signingConfig(sign), "tag", "-v", name)...)
+	return report, err
+}
+
+func signingConfig(sign *Signing) []string {
+	if sign == nil || sign.Format == "" {
+		return nil
+	}
+	return []string{"-c", "gpg.format=" + sign.Format}
+}
+
+func (b *ExecBackend) Push(ctx context.Context, remote, branch, tag string) error {
+	args := []string{"push", "--atomic", remote}
+	if branch != "" {
+		args = append(args, "HEAD:refs/heads/"+branch)
+	} else {
+		args = append(args, "HEAD")
+	}
+	if tag != "" {
+		args = append(args, "refs/tags/"+tag)
+	}
+	_, _, err := b.run(ctx, "", args...)
+	return err
+}
+
+func (b *ExecBackend) PushRef(ctx context.Context, remote, ref string) error {
+	_, _, err := b.run(ctx, "", "push", remote, ref+":"+ref)
+	return err
+}
+
+func (b *ExecBackend) RemoteURL(ctx context.Context, name string) (string, error) {
+	out, _, err := b.run(ctx, "", "remote", "get-url", name)
+	return strings.TrimSpace(out), err
+}
+
+func (b *ExecBackend) Tags(ctx context.Context) ([]string, error) {
+	out, _, err := b.run(ctx, "", "tag", "--list", "--merged", "HEAD")
+	return strings.Fields(out), err
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+func (b *ExecBackend) Log(ctx context.Context, since string) ([]Commit, error) {
+	args := []string{"log", "--format=%H%x1f%an%x1f%ae%x1f%P%x1f%B%x1e"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	} else {
+		args = append(args, "HEAD")
+	}
+
+	out, _, err := b.run(ctx, "", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, recordSep) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, fieldSep, 5)
+		if len(fields) != 5 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Merge:   len(strings.Fields(fields[3])) > 1,
+			Message: strings.TrimSpace(fields[4]),
+		})
+	}
+	return commits, nil
+}
+
+func (b *ExecBackend) Authors(ctx context.Context, since, until string) ([]Author, error) {
+	revs := until
+	if since != "" {
+		revs = since + ".." + until
+	}
+	// %aN and %aE apply .mailmap.
+	out, _, err := b.run(ctx, "", "log", "--format=%aN"+fieldSep+"%aE", revs, "--")
+	if err != nil {
+		return nil, err
+	}
+	var authors []Author
+	for _, line := range strings.Split(out, "\n") {
+		if name, email, ok := strings.Cut(line, fieldSep); ok {
+			authors = append(authors, Author{Name: name, Email: email})
+		}
+	}
+	return authors, nil
+}
+
+func (b *ExecBackend) DiffStat(ctx context.Context, from, to string) ([]FileStat, error) {
+	if from == "" {
+		out, _, err := b.run(ctx, "", "hash-object", "-t", "tree", "--stdin")
+		if err != nil {
+			return nil, err
+		}
+		from = strings.TrimSpace(out)
+	}
+	out, _, err := b.run(ctx, "", "diff", "--numstat", "-z", "--no-renames", from, to, "--")
+	if err != nil {
+		return nil, err
+	}
+	// Each record is "<insertions>\t<deletions>\t<path>", with "-" counts
+	// for binary files.
+	var stats []FileStat
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stat := FileStat{Path: fields[2], Binary: fields[0] == "-"}
+		if !stat.Binary {
+			if stat.Insertions, err = strconv.Atoi(fields[0]); err != nil {
+				return nil, fmt.Errorf("unexpected numstat output %q", record)
+			}
+			if stat.Deletions, err = strconv.Atoi(fields[1]); err != nil {
+				return nil, fmt.Errorf("unexpected numstat output %q", record)
+			}
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func (b *ExecBackend) Config(ctx context.Context, key string) (string, error) {
+	out, _, err := b.run(ctx, "", "config", "--get", key)
+	if exitCode(err) == 1 {
+		return "", nil
+	}
+	return strings.TrimSpace(out), err
+}
+
+func (b *ExecBackend) Branch(ctx context.Context) (string, error) {
+	out, _, err := b.run(ctx, "", "symbolic-ref", "--quiet", "--short", "HEAD")
+	if exitCode(err) == 1 {
+		return "", nil
+	}
+	return strings.TrimSpace(out), err
+}
+
+func (b *ExecBackend) Behind(ctx context.Context) (string, int, error) {
+	out, _, err := b.run(ctx, "", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return "", 0, nil
+	}
+	upstream := strings.TrimSpace(out)
+
+	out, _, err = b.run(ctx, "", "rev-list", "--count", "HEAD..@{upstream}")
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return "", 0, err
+	}
+	return upstream, n, nil
+}
+
+func (b *ExecBackend) TagExists(ctx context.Context, name string) (bool, error) {
+	_, _, err := b.run(ctx, "", "rev-parse", "--quiet", "--verify", "refs/tags/"+name)
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *ExecBackend) DeleteTag(ctx context.Context, name string) error {
+	_, _, err := b.run(ctx, "", "tag", "-d", name)
+	return err
+}
+
+func (b *ExecBackend) Resolve(ctx context.Context, rev string) (string, error) {
+	out, _, err := b.run(ctx, "", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if exitCode(err) == 1 {
+		return "", fmt.Errorf("%s: unknown revision", rev)
+	}
+	return strings.TrimSpace(out), err
+}
+
+func (b *ExecBackend) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	_, _, err := b.run(ctx, "", "merge-base", "--is-ancestor", ancestor, rev)
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *ExecBackend) ResetSoft(ctx context.Context, rev string) error {
+	_, _, err := b.run(ctx, "", "reset", "--soft", rev)
+	return err
+}
+
+func (b *ExecBackend) RemoteRefs(ctx context.Context, remote string) (map[string]string, error) {
+	out, _, err := b.run(ctx, "", "ls-remote", "--heads", "--tags", remote)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		hash, name, ok := strings.Cut(line, "\t")
+		// Skip the commits annotated tags point at.
+		if ok && !strings.HasSuffix(name, "^{}") {
+			refs[name] = hash
+		}
+	}
+	return refs, nil
+}
+
+func (b *ExecBackend) AddNote(ctx context.Context, ref, rev, note string) error {
+	// Notes go on the commit, not on the tag object an annotated tag names.
+	_, _, err := b.run(ctx, note, "notes", "--ref="+ref, "add", "--force", "--file=-", rev+"^{commit}")
+	return err
+}
+
+func (b *ExecBackend) RemoveNote(ctx context.Context, ref, rev string) error {
+	_, _, err := b.run(ctx, "", "notes", "--ref="+ref, "remove", "--ignore-missing", rev)
+	return err
+}
+
+func (b *ExecBackend) Notes(ctx context.Context, ref string) (map[string]string, error) {
+	out, _, err := b.run(ctx, "", "notes", "--ref="+ref, "list")
+	if err != nil {
+		return nil, err
+	}
+	// Each line is "<note blob> <commit>".
+	var blobs, commits []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if blob, commit, ok := strings.Cut(line, " "); ok {
+			blobs = append(blobs, blob)
+			commits = append(commits, commit)
+		}
+	}
+	notes := make(map[string]string, len(blobs))
+	if len(blobs) == 0 {
+		return notes, nil
+	}
+
+	out, _, err = b.run(ctx, strings.Join(blobs, "\n")+"\n", "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+	for _, commit := range commits {
+		// "<hash> <type> <size>\n<content>\n"
+		header, rest, ok := strings.Cut(out, "\n")
+		fields := strings.Fields(header)
+		if !ok || len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected cat-file output %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size+1 > len(rest) {
+			return nil, fmt.Errorf("unexpected cat-file output %q", header)
+		}
+		notes[commit] = rest[:size]
+		out = rest[size+1:]
+	}
+	return notes, nil
+}
+
+func (b *ExecBackend) CreateBranch(ctx context.Context, name, rev string) error {
+	_, _, err := b.run(ctx, "", "branch", "--no-track", name, rev)
+	return err
+}
+
+func (b *ExecBackend) Identity(ctx context.Context) (string, error) {
+	out, _, err := b.run(ctx, "", "-c", "user.useConfigOnly=true", "var", "GIT_COMMITTER_IDENT")
+	if err != nil {
+		return "", err
+	}
+	ident := strings.TrimSpace(out)
+	// Drop the timestamp following the email.
+	if i := strings.LastIndex(ident, ">"); i >= 0 {
+		ident = ident[:i+1]
+	}
+	return ident, nil
+}
+
+func (b *ExecBackend) HooksDir(ctx context.Context) (string, error) {
+	out, _, err := b.run(ctx, "", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(out))
+}
+
+func (b *ExecBackend) FileAt(ctx context.Context, rev, path string) ([]byte, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil {
+		return nil, err
+	}
+	// A path starting with ./ is relative to the working directory rather
+	// than the repository root.
+	out, _, err := b.run(ctx, "", "cat-file", "blob", rev+":./"+filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
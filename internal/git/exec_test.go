@@ -0,0 +1,76 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecBackend_CommandError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	r := newTestRepo(t)
+	hooks := filepath.Join(r.dir, ".git", "hooks")
+	if err := os.MkdirAll(hooks, 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook := "#!/bin/sh\necho 'commit-msg: missing ticket reference' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(hooks, "commit-msg"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(WithBackend(NewExecBackend()))
+	err := s.Commit(context.Background(), "Release 1.0.0", []string{r.write(t, "VERSION.md", "1.0.0")})
+	if !errors.Is(err, ErrCommitFailed) {
+		t.Fatalf("Commit() error = %v, want %v", err, ErrCommitFailed)
+	}
+
+	var cerr *CommandError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Commit() error = %v, want a *CommandError", err)
+	}
+	if cerr.ExitCode != 1 || !strings.Contains(cerr.Stderr, "missing ticket reference") {
+		t.Errorf("CommandError = exit code %d, stderr %q", cerr.ExitCode, cerr.Stderr)
+	}
+	if !strings.Contains(err.Error(), "git commit: exit status 1: commit-msg: missing ticket reference") {
+		t.Errorf("Error() = %q", err)
+	}
+}
+
+func TestCommandError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *CommandError
+		want string
+	}{
+		{
+			name: "stderr",
+			err:  &CommandError{Args: []string{"tag", "v1.0.0"}, ExitCode: 128, Stderr: "fatal: tag 'v1.0.0' already exists", Err: errors.New("exit status 128")},
+			want: "git tag: exit status 128: fatal: tag 'v1.0.0' already exists",
+		},
+		{
+			name: "config options are skipped",
+			err:  &CommandError{Args: []string{"-c", "gpg.format=ssh", "tag", "-v", "v1.0.0"}, ExitCode: 1, Err: errors.New("exit status 1")},
+			want: "git tag: exit status 1",
+		},
+		{
+			name: "git not run",
+			err:  &CommandError{Args: []string{"status"}, ExitCode: -1, Err: exec.ErrNotFound},
+			want: "git status: executable file not found in $PATH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
+	"regexp"
+	"strings"
 
 	"github.com/WagnerMatos/semver/internal/version"
 )
@@ -13,47 +14,482 @@ var (
 	ErrCommitFailed = errors.New("commit failed")
 	ErrAddFailed    = errors.New("add failed")
 	ErrTagFailed    = errors.New("tag failed")
+	ErrRemoteFailed = errors.New("remote lookup failed")
+	ErrLogFailed    = errors.New("log failed")
+	ErrConfigFailed = errors.New("config lookup failed")
+	ErrVerifyFailed = errors.New("tag verification failed")
+	ErrPushFailed   = errors.New("push failed")
+	ErrStatusFailed = errors.New("status failed")
+	ErrShowFailed   = errors.New("reading file from history failed")
+	ErrBranchFailed = errors.New("branch creation failed")
+	ErrResetFailed  = errors.New("reset failed")
+	ErrNotesFailed  = errors.New("notes failed")
+	// ErrStagedChanges reports changes staged before a release commit that
+	// the release didn't make.
+	ErrStagedChanges = errors.New("other changes are already staged")
 )
 
+// TrailerToken matches what git interpret-trailers accepts as a trailer
+// token, such as Signed-off-by.
+var TrailerToken = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// Commit is a commit as read from the history.
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Message string
+	Merge   bool
+}
+
 type Service interface {
-	Commit(context.Context, string) error
-	Tag(context.Context, *version.Version) error
+	Commit(ctx context.Context, message string, files []string) error
+	CheckStaged(ctx context.Context, files []string) error
+	Tag(context.Context, *version.Version, string) error
+	VerifyTag(context.Context, string) (string, error)
+	Push(ctx context.Context, remote, branch, tag string) error
+	RemoteURL(context.Context, string) (string, error)
+	LatestTag(context.Context) (string, error)
+	Log(context.Context, string) ([]Commit, error)
+	Author(context.Context) (string, error)
+}
+
+// Signing configures signed release tags. Key selects the signing key, as
+// with git tag -u; when empty, git's user.signingKey or default identity is
+// used. Format sets gpg.format (openpgp, x509 or ssh) and defaults to git's
+// own setting.
+type Signing struct {
+	Key    string
+	Format string
+}
+
+type GitService struct {
+	backend       Backend
+	tags          *TagFormat
+	signing       *Signing
+	includeStaged bool
+	signOff       bool
+	yanked        map[string]bool
+}
+
+type Option func(*GitService)
+
+// WithBackend runs git operations through b instead of the backend
+// NewBackend picks by default.
+func WithBackend(b Backend) Option {
+	return func(s *GitService) {
+		s.backend = b
+	}
+}
+
+// WithTagFormat names release tags, and recognises them in the history,
+// with f instead of the default format.
+func WithTagFormat(f *TagFormat) Option {
+	return func(s *GitService) {
+		s.tags = f
+	}
+}
+
+// WithSigning signs every tag created by the service.
+func WithSigning(sig Signing) Option {
+	return func(s *GitService) {
+		s.signing = &sig
+	}
 }
 
-type GitService struct{}
+// WithIncludeStaged lets commits include changes that were staged before
+// the release instead of refusing them.
+func WithIncludeStaged(include bool) Option {
+	return func(s *GitService) {
+		s.includeStaged = include
+	}
+}
 
-func New() *GitService {
-	return &GitService{}
+// WithSignOff adds a Signed-off-by trailer to commits, as git commit -s
+// does.
+func WithSignOff(signOff bool) Option {
+	return func(s *GitService) {
+		s.signOff = signOff
+	}
 }
 
-func (s *GitService) Commit(ctx context.Context, message string) error {
-	if err := s.add(ctx); err != nil {
+// WithYanked makes LatestTag pass over the releases of versions, which were
+// yanked, so that the next release covers their changes again.
+func WithYanked(versions ...string) Option {
+	return func(s *GitService) {
+		s.yanked = make(map[string]bool, len(versions))
+		for _, v := range versions {
+			s.yanked[v] = true
+		}
+	}
+}
+
+func New(opts ...Option) *GitService {
+	s := &GitService{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.backend == nil {
+		s.backend, _ = NewBackend("")
+	}
+	return s
+}
+
+// CheckStaged fails with ErrStagedChanges, listing the paths, when changes
+// other than files are staged, unless the service was created
+// WithIncludeStaged. Commit checks this too, but releases check it before
+// writing any file so that a refusal leaves the tree as it was.
+func (s *GitService) CheckStaged(ctx context.Context, files []string) error {
+	if s.includeStaged {
+		return nil
+	}
+	staged, err := s.backend.Staged(ctx, files)
+	if err != nil {
+		return fmt.Errorf("%w: listing staged changes: %w", ErrAddFailed, err)
+	}
+	if len(staged) > 0 {
+		return fmt.Errorf("%w; commit or unstage them first, or include them with --include-staged (includeStaged in the config):\n  %s",
+			ErrStagedChanges, strings.Join(staged, "\n  "))
+	}
+	return nil
+}
+
+// Commit stages files and commits them. Any other staged change makes it
+// fail as CheckStaged does.
+func (s *GitService) Commit(ctx context.Context, message string, files []string) error {
+	if err := s.CheckStaged(ctx, files); err != nil {
 		return err
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "commit", "-m", message)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%w: %v", ErrCommitFailed, err)
+	if err := s.backend.Add(ctx, files); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddFailed, err)
+	}
+	if err := s.backend.Commit(ctx, message, s.signOff); err != nil {
+		return fmt.Errorf("%w: %w", ErrCommitFailed, err)
 	}
+	return nil
+}
 
+// Tag creates the release tag for ver. A non-empty message makes it an
+// annotated tag; signed tags are always annotated and fall back to a
+// one-line message.
+func (s *GitService) Tag(ctx context.Context, ver *version.Version, message string) error {
+	tagName := s.tags.Name(ver)
+	if s.signing != nil && message == "" {
+		message = "Release " + tagName
+	}
+	if err := s.backend.CreateTag(ctx, tagName, message, s.signing); err != nil {
+		return fmt.Errorf("%w: %w", ErrTagFailed, err)
+	}
 	return nil
 }
 
-func (s *GitService) Tag(ctx context.Context, ver *version.Version) error {
-	tagName := fmt.Sprintf("v%s", ver.String())
-	cmd := exec.CommandContext(ctx, "git", "tag", tagName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%w: %v", ErrTagFailed, err)
+// VerifyTag checks the signature of tag and returns git's report of it.
+func (s *GitService) VerifyTag(ctx context.Context, tag string) (string, error) {
+	report, err := s.backend.VerifyTag(ctx, tag, s.signing)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrVerifyFailed, tag, err)
 	}
+	return report, nil
+}
 
+// Push sends HEAD to branch on remote together with tag, atomically: either
+// both refs are updated or neither is. An empty branch pushes to the branch
+// of the same name, and an empty tag pushes the commit alone.
+func (s *GitService) Push(ctx context.Context, remote, branch, tag string) error {
+	if err := s.backend.Push(ctx, remote, branch, tag); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrPushFailed, remote, err)
+	}
 	return nil
 }
 
-func (s *GitService) add(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, "git", "add", ".")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%w: %v", ErrAddFailed, err)
+// PushRef sends ref, such as a notes ref, to remote on its own.
+func (s *GitService) PushRef(ctx context.Context, remote, ref string) error {
+	if err := s.backend.PushRef(ctx, remote, ref); err != nil {
+		return fmt.Errorf("%w: %s: %s: %w", ErrPushFailed, remote, ref, err)
 	}
 	return nil
 }
 
+func (s *GitService) RemoteURL(ctx context.Context, name string) (string, error) {
+	url, err := s.backend.RemoteURL(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRemoteFailed, err)
+	}
+	return url, nil
+}
+
+// LatestTag returns the highest release tag reachable from HEAD that wasn't
+// yanked, or "" when there is none.
+func (s *GitService) LatestTag(ctx context.Context) (string, error) {
+	return s.latestTag(ctx, func(v *version.Version) bool { return !s.yanked[v.String()] })
+}
+
+// LatestLineTag returns the highest release tag of line reachable from HEAD,
+// or "" when there is none.
+func (s *GitService) LatestLineTag(ctx context.Context, line version.Line) (string, error) {
+	return s.latestTag(ctx, line.Contains)
+}
+
+func (s *GitService) latestTag(ctx context.Context, keep func(*version.Version) bool) (string, error) {
+	tags, err := s.backend.Tags(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrLogFailed, err)
+	}
+
+	var (
+		latest    string
+		latestVer *version.Version
+	)
+	for _, tag := range tags {
+		ver, ok := s.tags.Parse(tag)
+		if ok && keep(ver) && (latestVer == nil || ver.Compare(latestVer) > 0) {
+			latest, latestVer = tag, ver
+		}
+	}
+	return latest, nil
+}
+
+// Author returns the name to credit for changes made in this repository:
+// the github.user setting as an @handle when present, otherwise user.name.
+func (s *GitService) Author(ctx context.Context) (string, error) {
+	if login, err := s.backend.Config(ctx, "github.user"); err == nil && login != "" {
+		return "@" + strings.TrimPrefix(login, "@"), nil
+	}
+	if name, err := s.backend.Config(ctx, "user.name"); err == nil && name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: neither github.user nor user.name is set", ErrConfigFailed)
+}
+
+// Handle returns the login hidden in a GitHub noreply address such as
+// 123+alice@users.noreply.github.com as an @handle, or name for any other
+// address.
+func Handle(name, email string) string {
+	local, ok := strings.CutSuffix(email, "@users.noreply.github.com")
+	if !ok {
+		return name
+	}
+	if _, login, found := strings.Cut(local, "+"); found {
+		return "@" + login
+	}
+	return "@" + local
+}
+
+// Log returns the commits reachable from HEAD but not from since, newest
+// first. An empty since returns the whole history.
+func (s *GitService) Log(ctx context.Context, since string) ([]Commit, error) {
+	commits, err := s.backend.Log(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLogFailed, err)
+	}
+	return commits, nil
+}
+
+// Changes lists the tracked files with staged or unstaged changes, other
+// than exclude. Untracked files are left out, as releases never commit them.
+func (s *GitService) Changes(ctx context.Context, exclude []string) ([]string, error) {
+	changes, err := s.backend.Changes(ctx, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return changes, nil
+}
+
+// Unstaged lists the tracked files with changes that aren't staged, other
+// than exclude.
+func (s *GitService) Unstaged(ctx context.Context, exclude []string) ([]string, error) {
+	unstaged, err := s.backend.Unstaged(ctx, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return unstaged, nil
+}
+
+// Branch returns the name of the current branch, or "" when HEAD is
+// detached.
+func (s *GitService) Branch(ctx context.Context) (string, error) {
+	branch, err := s.backend.Branch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return branch, nil
+}
+
+// Behind returns the upstream of the current branch and how many of its
+// commits HEAD lacks, as of the last fetch. The upstream is "" when none
+// is configured.
+func (s *GitService) Behind(ctx context.Context) (string, int, error) {
+	upstream, n, err := s.backend.Behind(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return upstream, n, nil
+}
+
+// TagExists reports whether tag exists locally.
+func (s *GitService) TagExists(ctx context.Context, tag string) (bool, error) {
+	ok, err := s.backend.TagExists(ctx, tag)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return ok, nil
+}
+
+// Add stages files, including the removal of those that no longer exist.
+func (s *GitService) Add(ctx context.Context, files []string) error {
+	if err := s.backend.Add(ctx, files); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddFailed, err)
+	}
+	return nil
+}
+
+// DeleteTag deletes a local tag.
+func (s *GitService) DeleteTag(ctx context.Context, tag string) error {
+	if err := s.backend.DeleteTag(ctx, tag); err != nil {
+		return fmt.Errorf("%w: deleting %s: %w", ErrTagFailed, tag, err)
+	}
+	return nil
+}
+
+// Resolve returns the hash of the commit rev points at.
+func (s *GitService) Resolve(ctx context.Context, rev string) (string, error) {
+	hash, err := s.backend.Resolve(ctx, rev)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrStatusFailed, err)
+	}
+	return hash, nil
+}
+
+// IsAncestor reports whether the commit ancestor names is part of the
+// history of rev.
+func (s *GitService) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	ok, err := s.backend.IsAncestor(ctx, ancestor, rev)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrLogFailed, err)
+	}
+	return ok, nil
+}
+
+// ResetSoft moves the current branch to rev, keeping the changes of the
+// commits it drops staged.
+func (s *GitService) ResetSoft(ctx context.Context, rev string) error {
+	if err := s.backend.ResetSoft(ctx, rev); err != nil {
+		return fmt.Errorf("%w: %w", ErrResetFailed, err)
+	}
+	return nil
+}
+
+// RemoteRefs asks remote for its branches and tags and the hashes they
+// point at.
+func (s *GitService) RemoteRefs(ctx context.Context, remote string) (map[string]string, error) {
+	refs, err := s.backend.RemoteRefs(ctx, remote)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrRemoteFailed, remote, err)
+	}
+	return refs, nil
+}
+
+// ReleaseBranchPrefix starts the names of maintenance branches, such as
+// release/1.4, which only take patch releases of their line.
+const ReleaseBranchPrefix = "release/"
+
+// ReleaseBranch returns the name of the maintenance branch of line.
+func ReleaseBranch(line version.Line) string {
+	return ReleaseBranchPrefix + line.String()
+}
+
+// ParseReleaseBranch returns the line a maintenance branch is for. Other
+// branches report false.
+func ParseReleaseBranch(branch string) (version.Line, bool) {
+	s, ok := strings.CutPrefix(branch, ReleaseBranchPrefix)
+	if !ok {
+		return version.Line{}, false
+	}
+	line, err := version.ParseLine(s)
+	return line, err == nil
+}
+
+// MaintenanceLine returns the line of the maintenance branch checked out,
+// or nil on any other branch, together with the highest release tagged on
+// it, nil when the line has no tag yet.
+func (s *GitService) MaintenanceLine(ctx context.Context) (*version.Line, *version.Version, error) {
+	branch, err := s.Branch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	line, ok := ParseReleaseBranch(branch)
+	if !ok {
+		return nil, nil, nil
+	}
+	tag, err := s.LatestLineTag(ctx, line)
+	if err != nil {
+		return nil, nil, err
+	}
+	latest, _ := s.tags.Parse(tag)
+	return &line, latest, nil
+}
+
+// CreateBranch creates branch at rev without checking it out.
+func (s *GitService) CreateBranch(ctx context.Context, branch, rev string) error {
+	if err := s.backend.CreateBranch(ctx, branch, rev); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrBranchFailed, branch, err)
+	}
+	return nil
+}
+
+// AddNote attaches note to the commit rev points at under the notes ref,
+// replacing the note already there.
+func (s *GitService) AddNote(ctx context.Context, ref, rev, note string) error {
+	if err := s.backend.AddNote(ctx, ref, rev, note); err != nil {
+		return fmt.Errorf("%w: adding note to %s: %w", ErrNotesFailed, rev, err)
+	}
+	return nil
+}
+
+// RemoveNote removes the note on the commit rev names under the notes ref,
+// if there is one.
+func (s *GitService) RemoveNote(ctx context.Context, ref, rev string) error {
+	if err := s.backend.RemoveNote(ctx, ref, rev); err != nil {
+		return fmt.Errorf("%w: removing note from %s: %w", ErrNotesFailed, rev, err)
+	}
+	return nil
+}
+
+// Notes returns the notes under ref by the hash of the commit they are
+// attached to.
+func (s *GitService) Notes(ctx context.Context, ref string) (map[string]string, error) {
+	notes, err := s.backend.Notes(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotesFailed, err)
+	}
+	return notes, nil
+}
+
+// Identity returns the committer identity git would record, failing when
+// no name or email is configured rather than guessing one.
+func (s *GitService) Identity(ctx context.Context) (string, error) {
+	ident, err := s.backend.Identity(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrConfigFailed, err)
+	}
+	return ident, nil
+}
+
+// HooksDir returns the directory git runs hooks from.
+func (s *GitService) HooksDir(ctx context.Context) (string, error) {
+	dir, err := s.backend.HooksDir(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrConfigFailed, err)
+	}
+	return dir, nil
+}
+
+// FileAt returns the content of path as of the commit rev points at.
+func (s *GitService) FileAt(ctx context.Context, rev, path string) ([]byte, error) {
+	data, err := s.backend.FileAt(ctx, rev, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrShowFailed, err)
+	}
+	return data, nil
+}
@@ -2,9 +2,11 @@ package git
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -73,7 +75,7 @@ func TestGitService_Tag(t *testing.T) {
 	}
 
 	s := New()
-	if err := s.Commit(context.Background(), "initial commit"); err != nil {
+	if err := s.Commit(context.Background(), "initial commit", []string{"test.txt"}); err != nil {
 		t.Fatalf("Failed to make initial commit: %v", err)
 	}
 
@@ -99,7 +101,7 @@ func TestGitService_Tag(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := s.Tag(context.Background(), tt.version)
+			err := s.Tag(context.Background(), tt.version, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Tag() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -127,12 +129,120 @@ func TestGitService_Commit(t *testing.T) {
 		t.Skip("git is not installed")
 	}
 
-	dir := setupGitRepo(t)
-	testFile := filepath.Join(dir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
 	}
+	defer os.Chdir(originalDir)
+
+	tests := []struct {
+		name          string
+		message       string
+		staged        []string
+		includeStaged bool
+		signOff       bool
+		want          []string
+		wantErr       error
+	}{
+		{
+			name:    "only release files are committed",
+			message: "Release 1.0.0",
+			want:    []string{"CHANGELOG.md", "VERSION.md"},
+		},
+		{
+			name:    "release files staged beforehand",
+			message: "Release 1.0.0",
+			staged:  []string{"VERSION.md"},
+			want:    []string{"CHANGELOG.md", "VERSION.md"},
+		},
+		{
+			name:    "other staged changes are refused",
+			message: "Release 1.0.0",
+			staged:  []string{"VERSION.md", ".env", "debug.log"},
+			wantErr: ErrStagedChanges,
+		},
+		{
+			name:          "other staged changes included on request",
+			message:       "Release 1.0.0",
+			staged:        []string{"debug.log"},
+			includeStaged: true,
+			want:          []string{"CHANGELOG.md", "VERSION.md", "debug.log"},
+		},
+		{
+			name:    "signed off",
+			message: "Release 1.0.0",
+			signOff: true,
+			want:    []string{"CHANGELOG.md", "VERSION.md"},
+		},
+		{
+			name:    "empty message",
+			wantErr: ErrCommitFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupGitRepo(t)
+			for _, name := range []string{"VERSION.md", "CHANGELOG.md", ".env", "debug.log", "stray.txt"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+					t.Fatalf("Failed to create %s: %v", name, err)
+				}
+			}
+			for _, name := range tt.staged {
+				gitRun(t, dir, "add", name)
+			}
+
+			// Run from a subdirectory to check that paths are resolved
+			// against the repository root.
+			sub := filepath.Join(dir, "sub")
+			if err := os.Mkdir(sub, 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.Chdir(sub); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+
+			files := []string{filepath.Join(dir, "VERSION.md"), filepath.Join(dir, "CHANGELOG.md")}
+			err := New(WithIncludeStaged(tt.includeStaged), WithSignOff(tt.signOff)).Commit(context.Background(), tt.message, files)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Commit() error = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrStagedChanges) && (!strings.Contains(err.Error(), ".env") || !strings.Contains(err.Error(), "debug.log")) {
+				t.Errorf("Commit() error = %v, want the staged paths listed", err)
+			}
+			if err != nil {
+				return
+			}
+
+			got := strings.Fields(gitOutput(t, dir, "show", "--name-only", "--format=", "HEAD"))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("committed files = %v, want %v", got, tt.want)
+			}
+			trailers := gitOutput(t, dir, "log", "-1", "--format=%(trailers:key=Signed-off-by,valueonly)")
+			if signedOff := trailers != ""; signedOff != tt.signOff {
+				t.Errorf("Signed-off-by = %q, want signed off %v", trailers, tt.signOff)
+			}
+		})
+	}
+}
 
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestGitService_AnnotatedTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := setupGitRepo(t)
 	originalDir, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Failed to get current directory: %v", err)
@@ -142,32 +252,592 @@ func TestGitService_Commit(t *testing.T) {
 	if err := os.Chdir(dir); err != nil {
 		t.Fatalf("Failed to change directory: %v", err)
 	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
 
 	tests := []struct {
 		name    string
+		version *version.Version
 		message string
+		want    string
+	}{
+		{
+			name:    "lightweight without a message",
+			version: &version.Version{Major: 1},
+			want:    "commit",
+		},
+		{
+			name:    "annotated keeps markdown headings",
+			version: &version.Version{Major: 1, Minor: 1},
+			message: "Release 1.1.0\n\n### Added\n- Feature",
+			want:    "tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().Tag(context.Background(), tt.version, tt.message); err != nil {
+				t.Fatalf("Tag() error = %v", err)
+			}
+
+			tag := "v" + tt.version.String()
+			if got := gitOutput(t, dir, "cat-file", "-t", tag); got != tt.want {
+				t.Errorf("tag object type = %s, want %s", got, tt.want)
+			}
+			if tt.message == "" {
+				return
+			}
+			if got := gitOutput(t, dir, "tag", "-l", "--format=%(contents)", tag); got != tt.message {
+				t.Errorf("tag message = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestGitService_SignedTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	dir := setupGitRepo(t)
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, out)
+	}
+	pub, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+	signers := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(signers, []byte("test@example.com "+string(pub)), 0644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+	gitRun(t, dir, "config", "gpg.ssh.allowedSignersFile", signers)
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, dir, "tag", "-a", "-m", "unsigned", "v0.9.0")
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	ctx := context.Background()
+	s := New(WithSigning(Signing{Key: key, Format: "ssh"}))
+	if err := s.Tag(ctx, &version.Version{Major: 1}, ""); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	if got := gitOutput(t, dir, "tag", "-l", "--format=%(contents:subject)", "v1.0.0"); got != "Release v1.0.0" {
+		t.Errorf("tag subject = %q, want the default message", got)
+	}
+
+	report, err := s.VerifyTag(ctx, "v1.0.0")
+	if err != nil {
+		t.Fatalf("VerifyTag() error = %v", err)
+	}
+	if !strings.Contains(report, "Good") {
+		t.Errorf("VerifyTag() report = %q", report)
+	}
+
+	if _, err := s.VerifyTag(ctx, "v0.9.0"); !errors.Is(err, ErrVerifyFailed) {
+		t.Errorf("VerifyTag() of an unsigned tag error = %v, want %v", err, ErrVerifyFailed)
+	}
+}
+
+func TestGitService_Push(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tests := []struct {
+		name    string
+		branch  string
+		tag     string
+		diverge bool
+		wantErr error
+	}{
+		{name: "commit and tag", tag: "v1.0.0"},
+		{name: "configured branch", branch: "main", tag: "v1.0.0"},
+		{name: "commit only"},
+		{name: "rejected push updates nothing", tag: "v1.0.0", diverge: true, wantErr: ErrPushFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := t.TempDir()
+			gitRun(t, remote, "init", "--bare")
+
+			dir := setupGitRepo(t)
+			gitRun(t, dir, "checkout", "-b", "release")
+			gitRun(t, dir, "remote", "add", "origin", remote)
+			gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
+			gitRun(t, dir, "push", "origin", "release")
+
+			if tt.diverge {
+				other := setupGitRepo(t)
+				gitRun(t, other, "remote", "add", "origin", remote)
+				gitRun(t, other, "fetch", "origin")
+				gitRun(t, other, "checkout", "-b", "release", "origin/release")
+				gitRun(t, other, "commit", "--allow-empty", "-m", "concurrent")
+				gitRun(t, other, "push", "origin", "release")
+			}
+
+			gitRun(t, dir, "commit", "--allow-empty", "-m", "Release 1.0.0")
+			if tt.tag != "" {
+				gitRun(t, dir, "tag", tt.tag)
+			}
+
+			originalDir, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("Failed to get current directory: %v", err)
+			}
+			defer os.Chdir(originalDir)
+
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+
+			err = New().Push(context.Background(), "origin", tt.branch, tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Push() error = %v, want %v", err, tt.wantErr)
+			}
+
+			tags := gitOutput(t, remote, "tag")
+			if tt.wantErr != nil {
+				if tags != "" {
+					t.Errorf("remote tags = %q after a rejected push, want none", tags)
+				}
+				return
+			}
+
+			branch := tt.branch
+			if branch == "" {
+				branch = "release"
+			}
+			head := gitOutput(t, dir, "rev-parse", "HEAD")
+			if got := gitOutput(t, remote, "rev-parse", "refs/heads/"+branch); got != head {
+				t.Errorf("remote %s = %s, want %s", branch, got, head)
+			}
+			if tags != tt.tag {
+				t.Errorf("remote tags = %q, want %q", tags, tt.tag)
+			}
+		})
+	}
+}
+
+func TestGitService_RepositoryState(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("EMAIL", "")
+
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--bare")
+	dir := setupGitRepo(t)
+	gitRun(t, dir, "checkout", "-b", "main")
+	for _, name := range []string{"VERSION.md", "a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("1"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-m", "initial")
+	gitRun(t, dir, "tag", "v1.0.0")
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	s := New()
+	ctx := context.Background()
+
+	if changes, err := s.Changes(ctx, nil); err != nil || len(changes) != 0 {
+		t.Errorf("Changes() on a clean tree = %v, %v", changes, err)
+	}
+	for _, name := range []string{"VERSION.md", "a.go", "untracked.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("2"), 0644); err != nil {
+			t.Fatalf("Failed to modify %s: %v", name, err)
+		}
+	}
+	gitRun(t, dir, "mv", "b.go", "c.go")
+	changes, err := s.Changes(ctx, []string{filepath.Join(dir, "VERSION.md")})
+	if want := []string{"a.go", "c.go"}; err != nil || !slices.Equal(changes, want) {
+		t.Errorf("Changes() = %v, %v; want %v", changes, err, want)
+	}
+
+	if branch, err := s.Branch(ctx); err != nil || branch != "main" {
+		t.Errorf("Branch() = %q, %v; want main", branch, err)
+	}
+
+	if upstream, n, err := s.Behind(ctx); err != nil || upstream != "" || n != 0 {
+		t.Errorf("Behind() without upstream = %q, %d, %v", upstream, n, err)
+	}
+	gitRun(t, dir, "remote", "add", "origin", remote)
+	gitRun(t, dir, "stash")
+	gitRun(t, dir, "push", "-u", "origin", "main")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "second")
+	gitRun(t, dir, "push", "origin", "main")
+	gitRun(t, dir, "reset", "--hard", "HEAD~1")
+	if upstream, n, err := s.Behind(ctx); err != nil || upstream != "origin/main" || n != 1 {
+		t.Errorf("Behind() = %q, %d, %v; want origin/main, 1", upstream, n, err)
+	}
+
+	for tag, want := range map[string]bool{"v1.0.0": true, "v1.1.0": false} {
+		if got, err := s.TagExists(ctx, tag); err != nil || got != want {
+			t.Errorf("TagExists(%s) = %v, %v; want %v", tag, got, err, want)
+		}
+	}
+
+	if ident, err := s.Identity(ctx); err != nil || ident != "Test User <test@example.com>" {
+		t.Errorf("Identity() = %q, %v", ident, err)
+	}
+	gitRun(t, dir, "config", "--unset", "user.email")
+	if _, err := s.Identity(ctx); !errors.Is(err, ErrConfigFailed) {
+		t.Errorf("Identity() without an email error = %v, want %v", err, ErrConfigFailed)
+	}
+
+	gitRun(t, dir, "checkout", "--detach")
+	if branch, err := s.Branch(ctx); err != nil || branch != "" {
+		t.Errorf("Branch() on a detached HEAD = %q, %v", branch, err)
+	}
+}
+
+func TestGitService_RemoteURL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := setupGitRepo(t)
+	cmd := exec.Command("git", "remote", "add", "origin", "git@github.com:org/repo.git")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		want    string
 		wantErr bool
 	}{
 		{
-			name:    "valid commit",
-			message: "test commit",
-			wantErr: false,
+			name:   "existing remote",
+			remote: "origin",
+			want:   "git@github.com:org/repo.git",
 		},
 		{
-			name:    "empty message",
-			message: "",
+			name:    "missing remote",
+			remote:  "upstream",
 			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := New()
-			err := s.Commit(context.Background(), tt.message)
+			got, err := New().RemoteURL(context.Background(), tt.remote)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Commit() error = %v, wantErr %v", err, tt.wantErr)
+				t.Fatalf("RemoteURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RemoteURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestGitService_LogAndLatestTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := setupGitRepo(t)
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	s := New()
+	ctx := context.Background()
+
+	if tag, err := s.LatestTag(ctx); err == nil && tag != "" {
+		t.Errorf("LatestTag() on empty repo = %q, want none", tag)
+	}
+
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, dir, "tag", "v0.9.0")
+	gitRun(t, dir, "tag", "v1.0.0")
+	gitRun(t, dir, "tag", "not-a-release")
+	gitRun(t, dir, "tag", "api/v2.0.0")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "feat: one\n\nbody text")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "fix: two")
+
+	tag, err := s.LatestTag(ctx)
+	if err != nil {
+		t.Fatalf("LatestTag() error = %v", err)
+	}
+	if tag != "v1.0.0" {
+		t.Errorf("LatestTag() = %q, want v1.0.0", tag)
+	}
+
+	if tag, err := New(WithYanked("1.0.0")).LatestTag(ctx); err != nil || tag != "v0.9.0" {
+		t.Errorf("LatestTag() with 1.0.0 yanked = %q, %v, want v0.9.0", tag, err)
+	}
+
+	api, err := NewTagFormat("{{.Package}}/v{{.Version}}", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag, err := New(WithTagFormat(api)).LatestTag(ctx); err != nil || tag != "api/v2.0.0" {
+		t.Errorf("LatestTag() with the api format = %q, %v, want api/v2.0.0", tag, err)
+	}
+
+	commits, err := s.Log(ctx, tag)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Log() returned %d commits, want 2", len(commits))
+	}
+	if commits[0].Message != "fix: two" || commits[1].Message != "feat: one\n\nbody text" {
+		t.Errorf("Log() messages = %q, %q", commits[0].Message, commits[1].Message)
+	}
+	if commits[0].Author != "Test User" || commits[0].Email != "test@example.com" || len(commits[0].Hash) != 40 {
+		t.Errorf("Log() metadata = %+v", commits[0])
+	}
+
+	all, err := s.Log(ctx, "")
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Log() of full history returned %d commits, want 3", len(all))
+	}
+}
+
+func TestGitService_Stats(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := setupGitRepo(t)
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	commitAs := func(author, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", file)
+		gitRun(t, dir, "commit", "-m", "change "+file, "--author", author)
+	}
+	commitAs("Alice <alice@example.com>", "a.txt", "one\n")
+	gitRun(t, dir, "tag", "v1.0.0")
+	commitAs("Bob <bob@example.com>", "b.txt", "one\ntwo\n")
+	commitAs("alice <Alice@Example.com>", "a.txt", "two\n")
+	commitAs("Alice Doe <alice@example.com>", "a.txt", "three\n")
+
+	s := New()
+	stats, err := s.Stats(context.Background(), "v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	want := []Contributor{
+		{Author: Author{Name: "Alice Doe", Email: "alice@example.com"}, Commits: 2},
+		{Author: Author{Name: "Bob", Email: "bob@example.com"}, Commits: 1, FirstTime: true},
+	}
+	if stats.Commits != 3 || !slices.Equal(stats.Contributors, want) {
+		t.Errorf("Stats() = %d commits by %+v, want 3 by %+v", stats.Commits, stats.Contributors, want)
+	}
+	if first := stats.FirstTime(); len(first) != 1 || first[0].Name != "Bob" {
+		t.Errorf("FirstTime() = %+v, want Bob", first)
+	}
+	if len(stats.Files) != 2 || stats.Insertions != 3 || stats.Deletions != 1 {
+		t.Errorf("Stats() files = %+v, +%d -%d, want 2 files, +3 -1", stats.Files, stats.Insertions, stats.Deletions)
+	}
+
+	all, err := s.Stats(context.Background(), "", "v1.0.0")
+	if err != nil {
+		t.Fatalf("Stats() of the whole history error = %v", err)
+	}
+	if all.Commits != 1 || len(all.Contributors) != 1 || !all.Contributors[0].FirstTime {
+		t.Errorf("Stats() of the whole history = %+v, want one first-time contributor", all)
+	}
+
+	if _, err := s.Stats(context.Background(), "v9.9.9", "HEAD"); !errors.Is(err, ErrLogFailed) {
+		t.Errorf("Stats() from a missing tag error = %v, want ErrLogFailed", err)
+	}
+}
+
+func TestParseReleaseBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   version.Line
+		wantOK bool
+	}{
+		{branch: "release/1.4", want: version.Line{Major: 1, Minor: 4}, wantOK: true},
+		{branch: "release/0.12", want: version.Line{Major: 0, Minor: 12}, wantOK: true},
+		{branch: "release/1.4.2"},
+		{branch: "release/next"},
+		{branch: "main"},
+		{branch: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			got, ok := ParseReleaseBranch(tt.branch)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseReleaseBranch() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+			if ok && ReleaseBranch(got) != tt.branch {
+				t.Errorf("ReleaseBranch() = %q, want %q", ReleaseBranch(got), tt.branch)
 			}
 		})
 	}
 }
 
+func TestGitService_MaintenanceLine(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := setupGitRepo(t)
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	s := New()
+	ctx := context.Background()
+
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, dir, "tag", "v1.4.0")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "fix: one")
+	gitRun(t, dir, "tag", "v1.4.1")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "feat: two")
+	gitRun(t, dir, "tag", "v1.5.0")
+
+	if line, latest, err := s.MaintenanceLine(ctx); err != nil || line != nil || latest != nil {
+		t.Errorf("MaintenanceLine() on the main branch = %v, %v, %v", line, latest, err)
+	}
+
+	if err := s.CreateBranch(ctx, "release/1.4", "v1.4.1"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	if err := s.CreateBranch(ctx, "release/1.4", "v1.4.1"); !errors.Is(err, ErrBranchFailed) {
+		t.Errorf("CreateBranch() of an existing branch error = %v, want ErrBranchFailed", err)
+	}
+	gitRun(t, dir, "checkout", "-q", "release/1.4")
+
+	line, latest, err := s.MaintenanceLine(ctx)
+	if err != nil {
+		t.Fatalf("MaintenanceLine() error = %v", err)
+	}
+	if line == nil || *line != (version.Line{Major: 1, Minor: 4}) || latest == nil || latest.String() != "1.4.1" {
+		t.Errorf("MaintenanceLine() = %v, %v, want 1.4, 1.4.1", line, latest)
+	}
+	if tag, err := s.LatestLineTag(ctx, version.Line{Major: 1, Minor: 3}); err != nil || tag != "" {
+		t.Errorf("LatestLineTag(1.3) = %q, %v, want none", tag, err)
+	}
+}
+
+func TestGitService_Author(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// Keep a global github.user from leaking into the test.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := setupGitRepo(t)
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	s := New()
+	ctx := context.Background()
+
+	if got, err := s.Author(ctx); err != nil || got != "Test User" {
+		t.Errorf("Author() = %q, %v; want %q", got, err, "Test User")
+	}
+
+	gitRun(t, dir, "config", "github.user", "testuser")
+	if got, err := s.Author(ctx); err != nil || got != "@testuser" {
+		t.Errorf("Author() = %q, %v; want %q", got, err, "@testuser")
+	}
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name, email, want string
+	}{
+		{"Alice Smith", "1234+alice@users.noreply.github.com", "@alice"},
+		{"Alice Smith", "alice@users.noreply.github.com", "@alice"},
+		{"Alice Smith", "alice@example.com", "Alice Smith"},
+		{"alice", "alice@example.com", "alice"},
+	}
+
+	for _, tt := range tests {
+		if got := Handle(tt.name, tt.email); got != tt.want {
+			t.Errorf("Handle(%q, %q) = %q, want %q", tt.name, tt.email, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,965 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// GoGitBackend works on the repository in-process with go-git, for
+// environments without a git binary. It does not run hooks or sign tags,
+// and pushing to a remote on the local filesystem still needs git.
+type GoGitBackend struct{}
+
+func NewGoGitBackend() *GoGitBackend {
+	return &GoGitBackend{}
+}
+
+// open opens the repository containing the working directory.
+func (b *GoGitBackend) open() (*gogit.Repository, error) {
+	return gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+func (b *GoGitBackend) worktree() (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, wt, nil
+}
+
+// relPaths returns paths relative to the root of wt, with forward slashes
+// as in the index.
+func relPaths(wt *gogit.Worktree, paths []string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(wt.Filesystem.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	rel := make([]string, 0, len(paths))
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			abs = filepath.Join(dir, filepath.Base(abs))
+		}
+		r, err := filepath.Rel(root, abs)
+		if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside the repository", p)
+		}
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel, nil
+}
+
+func (b *GoGitBackend) Add(ctx context.Context, paths []string) error {
+	_, wt, err := b.worktree()
+	if err != nil {
+		return err
+	}
+	rel, err := relPaths(wt, paths)
+	if err != nil {
+		return err
+	}
+	for _, p := range rel {
+		if _, err := wt.Add(p); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (b *GoGitBackend) Commit(ctx context.Context, message string, signOff bool) error {
+	repo, wt, err := b.worktree()
+	if err != nil {
+		return err
+	}
+
+	// Clean the message up as git commit does by default.
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return errors.New("empty commit message")
+	}
+	if signOff {
+		ident, err := identity(repo)
+		if err != nil {
+			return err
+		}
+		message = addTrailer(message, "Signed-off-by: "+ident)
+	}
+
+	_, err = wt.Commit(message+"\n", &gogit.CommitOptions{})
+	return err
+}
+
+// addTrailer appends trailer to message, joining the trailer block that
+// ends it if there is one.
+func addTrailer(message, trailer string) string {
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) > 1 && isTrailerBlock(last) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+func isTrailerBlock(block string) bool {
+	for _, line := range strings.Split(block, "\n") {
+		token, _, ok := strings.Cut(line, ": ")
+		if !ok || !TrailerToken.MatchString(token) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *GoGitBackend) status(exclude []string) (gogit.Status, []string, error) {
+	_, wt, err := b.worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	excluded, err := relPaths(wt, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range excluded {
+		delete(status, p)
+	}
+
+	paths := make([]string, 0, len(status))
+	for p := range status {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return status, paths, nil
+}
+
+func changed(code gogit.StatusCode) bool {
+	return code != gogit.Unmodified && code != gogit.Untracked
+}
+
+func (b *GoGitBackend) Staged(ctx context.Context, exclude []string) ([]string, error) {
+	status, paths, err := b.status(exclude)
+	if err != nil {
+		return nil, err
+	}
+	var staged []string
+	for _, p := range paths {
+		if changed(status[p].Staging) {
+			staged = append(staged, p)
+		}
+	}
+	return staged, nil
+}
+
+func (b *GoGitBackend) Unstaged(ctx context.Context, exclude []string) ([]string, error) {
+	status, paths, err := b.status(exclude)
+	if err != nil {
+		return nil, err
+	}
+	var unstaged []string
+	for _, p := range paths {
+		if changed(status[p].Worktree) {
+			unstaged = append(unstaged, p)
+		}
+	}
+	return unstaged, nil
+}
+
+func (b *GoGitBackend) Changes(ctx context.Context, exclude []string) ([]string, error) {
+	status, paths, err := b.status(exclude)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, p := range paths {
+		if changed(status[p].Staging) || changed(status[p].Worktree) {
+			changes = append(changes, p)
+		}
+	}
+	return changes, nil
+}
+
+var errSigningUnsupported = fmt.Errorf("the go-git backend cannot sign or verify tags: %w", errors.ErrUnsupported)
+
+func (b *GoGitBackend) CreateTag(ctx context.Context, name, message string, sign *Signing) error {
+	if sign != nil {
+		return errSigningUnsupported
+	}
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	var opts *gogit.CreateTagOptions
+	if message != "" {
+		opts = &gogit.CreateTagOptions{Message: message}
+	}
+	_, err = repo.CreateTag(name, head.Hash(), opts)
+	return err
+}
+
+func (b *GoGitBackend) VerifyTag(ctx context.Context, name string, sign *Signing) (string, error) {
+	return "", errSigningUnsupported
+}
+
+func (b *GoGitBackend) Push(ctx context.Context, remote, branch, tag string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		if !head.Name().IsBranch() {
+			return errors.New("HEAD is detached; name the branch to push to")
+		}
+		branch = head.Name().Short()
+	}
+
+	specs := []gitconfig.RefSpec{gitconfig.RefSpec(head.Hash().String() + ":" + plumbing.NewBranchReferenceName(branch).String())}
+	if tag != "" {
+		ref := plumbing.NewTagReferenceName(tag).String()
+		specs = append(specs, gitconfig.RefSpec(ref+":"+ref))
+	}
+
+	err = repo.PushContext(ctx, &gogit.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   specs,
+		Atomic:     true,
+	})
+	if errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+func (b *GoGitBackend) PushRef(ctx context.Context, remote, ref string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	err = repo.PushContext(ctx, &gogit.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(ref + ":" + ref)},
+	})
+	if errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+func (b *GoGitBackend) RemoteURL(ctx context.Context, name string) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	r, err := repo.Remote(name)
+	if err != nil {
+		return "", err
+	}
+	if urls := r.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", fmt.Errorf("remote %s has no URL", name)
+}
+
+// reachable returns the commits reachable from hash.
+func reachable(repo *gogit.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&gogit.LogOptions{From: hash})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[plumbing.Hash]bool)
+	err = iter.ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	return seen, err
+}
+
+func (b *GoGitBackend) Tags(ctx context.Context) ([]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	merged, err := reachable(repo, head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+		if merged[hash] {
+			tags = append(tags, ref.Name().Short())
+		}
+		return nil
+	})
+	slices.Sort(tags)
+	return tags, err
+}
+
+func (b *GoGitBackend) Log(ctx context.Context, since string) ([]Commit, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	logged, err := logRange(repo, since, head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, c := range logged {
+		commits = append(commits, Commit{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Merge:   c.NumParents() > 1,
+			Message: strings.TrimSpace(c.Message),
+		})
+	}
+	return commits, nil
+}
+
+// logRange returns the commits reachable from until but not from since,
+// newest first.
+func logRange(repo *gogit.Repository, since string, until plumbing.Hash) ([]*object.Commit, error) {
+	exclude := map[plumbing.Hash]bool{}
+	if since != "" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(since))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", since, err)
+		}
+		if exclude, err = reachable(repo, *hash); err != nil {
+			return nil, err
+		}
+	}
+
+	iter, err := repo.Log(&gogit.LogOptions{From: until, Order: gogit.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	var commits []*object.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if !exclude[c.Hash] {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	return commits, err
+}
+
+func (b *GoGitBackend) Authors(ctx context.Context, since, until string) ([]Author, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := commitAt(repo, until)
+	if err != nil {
+		return nil, err
+	}
+	logged, err := logRange(repo, since, commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+	mm, err := readMailmap(repo)
+	if err != nil {
+		return nil, err
+	}
+	authors := make([]Author, 0, len(logged))
+	for _, c := range logged {
+		name, email := mm.resolve(c.Author.Name, c.Author.Email)
+		authors = append(authors, Author{Name: name, Email: email})
+	}
+	return authors, nil
+}
+
+// readMailmap reads the .mailmap file at the top of the working tree, if
+// there is one.
+func readMailmap(repo *gogit.Repository) (*mailmap, error) {
+	wt, err := repo.Worktree()
+	if errors.Is(err, gogit.ErrIsBareRepository) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := wt.Filesystem.Open(".mailmap")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return parseMailmap(string(data)), nil
+}
+
+func (b *GoGitBackend) DiffStat(ctx context.Context, from, to string) ([]FileStat, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	var trees [2]*object.Tree
+	for i, rev := range []string{from, to} {
+		if rev == "" {
+			continue
+		}
+		commit, err := commitAt(repo, rev)
+		if err != nil {
+			return nil, err
+		}
+		if trees[i], err = commit.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, trees[0], trees[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := changes.PatchContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []FileStat
+	for _, fp := range patch.FilePatches() {
+		before, after := fp.Files()
+		stat := FileStat{Binary: fp.IsBinary()}
+		if after != nil {
+			stat.Path = after.Path()
+		} else {
+			stat.Path = before.Path()
+		}
+		for _, chunk := range fp.Chunks() {
+			lines := strings.Count(chunk.Content(), "\n")
+			if c := chunk.Content(); c != "" && !strings.HasSuffix(c, "\n") {
+				lines++
+			}
+			switch chunk.Type() {
+			case diff.Add:
+				stat.Insertions += lines
+			case diff.Delete:
+				stat.Deletions += lines
+			}
+		}
+		stats = append(stats, stat)
+	}
+	slices.SortFunc(stats, func(a, b FileStat) int { return strings.Compare(a.Path, b.Path) })
+	return stats, nil
+}
+
+func (b *GoGitBackend) Config(ctx context.Context, key string) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	return configValue(repo, key)
+}
+
+// configValue looks key up in the repository, global and system
+// configuration, in that order.
+func configValue(repo *gogit.Repository, key string) (string, error) {
+	section, option, ok := strings.Cut(key, ".")
+	if !ok {
+		return "", fmt.Errorf("invalid config key %q", key)
+	}
+	var subsection string
+	if i := strings.LastIndex(option, "."); i >= 0 {
+		subsection, option = option[:i], option[i+1:]
+	}
+
+	local, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	configs := []*gitconfig.Config{local}
+	for _, scope := range []gitconfig.Scope{gitconfig.GlobalScope, gitconfig.SystemScope} {
+		cfg, err := gitconfig.LoadConfig(scope)
+		if err != nil {
+			return "", err
+		}
+		configs = append(configs, cfg)
+	}
+
+	for _, cfg := range configs {
+		if !cfg.Raw.HasSection(section) {
+			continue
+		}
+		s := cfg.Raw.Section(section)
+		var value string
+		switch {
+		case subsection == "":
+			value = s.Option(option)
+		case s.HasSubsection(subsection):
+			value = s.Subsection(subsection).Option(option)
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+func (b *GoGitBackend) Branch(ctx context.Context) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return "", nil
+	}
+	return head.Target().Short(), nil
+}
+
+func (b *GoGitBackend) Behind(ctx context.Context) (string, int, error) {
+	branch, err := b.Branch(ctx)
+	if err != nil || branch == "" {
+		return "", 0, err
+	}
+	repo, err := b.open()
+	if err != nil {
+		return "", 0, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", 0, err
+	}
+	bc, ok := cfg.Branches[branch]
+	if !ok || bc.Merge == "" {
+		return "", 0, nil
+	}
+
+	upstream, ref := bc.Merge.Short(), bc.Merge
+	if bc.Remote != "." {
+		upstream = bc.Remote + "/" + bc.Merge.Short()
+		ref = plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short())
+	}
+	up, err := repo.Reference(ref, true)
+	if err != nil {
+		// Not fetched yet.
+		return "", 0, nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", 0, err
+	}
+	have, err := reachable(repo, head.Hash())
+	if err != nil {
+		return "", 0, err
+	}
+	want, err := reachable(repo, up.Hash())
+	if err != nil {
+		return "", 0, err
+	}
+	n := 0
+	for hash := range want {
+		if !have[hash] {
+			n++
+		}
+	}
+	return upstream, n, nil
+}
+
+func (b *GoGitBackend) TagExists(ctx context.Context, name string) (bool, error) {
+	repo, err := b.open()
+	if err != nil {
+		return false, err
+	}
+	_, err = repo.Tag(name)
+	if errors.Is(err, gogit.ErrTagNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// commitAt returns the commit rev points at, peeling annotated tags.
+func commitAt(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rev, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		// An annotated tag.
+		var tag *object.Tag
+		if tag, err = repo.TagObject(*hash); err == nil {
+			commit, err = tag.Commit()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rev, err)
+	}
+	return commit, nil
+}
+
+func (b *GoGitBackend) DeleteTag(ctx context.Context, name string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	return repo.DeleteTag(name)
+}
+
+func (b *GoGitBackend) Resolve(ctx context.Context, rev string) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	commit, err := commitAt(repo, rev)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash.String(), nil
+}
+
+func (b *GoGitBackend) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	repo, err := b.open()
+	if err != nil {
+		return false, err
+	}
+	from, err := commitAt(repo, ancestor)
+	if err != nil {
+		return false, err
+	}
+	to, err := commitAt(repo, rev)
+	if err != nil {
+		return false, err
+	}
+	seen, err := reachable(repo, to.Hash)
+	if err != nil {
+		return false, err
+	}
+	return seen[from.Hash], nil
+}
+
+func (b *GoGitBackend) ResetSoft(ctx context.Context, rev string) error {
+	repo, wt, err := b.worktree()
+	if err != nil {
+		return err
+	}
+	commit, err := commitAt(repo, rev)
+	if err != nil {
+		return err
+	}
+	return wt.Reset(&gogit.ResetOptions{Commit: commit.Hash, Mode: gogit.SoftReset})
+}
+
+func (b *GoGitBackend) RemoteRefs(ctx context.Context, remote string) (map[string]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return nil, err
+	}
+	list, err := r.ListContext(ctx, &gogit.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, ref := range list {
+		if ref.Type() == plumbing.HashReference && (ref.Name().IsBranch() || ref.Name().IsTag()) {
+			refs[ref.Name().String()] = ref.Hash().String()
+		}
+	}
+	return refs, nil
+}
+
+func (b *GoGitBackend) AddNote(ctx context.Context, ref, rev, note string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	commit, err := commitAt(repo, rev)
+	if err != nil {
+		return err
+	}
+	name := plumbing.ReferenceName(ref)
+	notes, parent, err := readNotes(repo, name)
+	if err != nil {
+		return err
+	}
+	// git notes ends notes with a newline.
+	notes[commit.Hash.String()] = strings.TrimRight(note, "\n") + "\n"
+	return writeNotes(repo, name, parent, notes)
+}
+
+func (b *GoGitBackend) RemoveNote(ctx context.Context, ref, rev string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return err
+	}
+	name := plumbing.ReferenceName(ref)
+	notes, parent, err := readNotes(repo, name)
+	if err != nil {
+		return err
+	}
+	if _, ok := notes[hash.String()]; !ok {
+		return nil
+	}
+	delete(notes, hash.String())
+	return writeNotes(repo, name, parent, notes)
+}
+
+// writeNotes commits notes as the new content of the notes ref, on top of
+// parent. Every note is written flat at the root of the tree, which git
+// reads along with fanned-out notes.
+func writeNotes(repo *gogit.Repository, ref plumbing.ReferenceName, parent plumbing.Hash, notes map[string]string) error {
+	tree := &object.Tree{}
+	for target, content := range notes {
+		blob := repo.Storer.NewEncodedObject()
+		blob.SetType(plumbing.BlobObject)
+		w, err := blob.Writer()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		hash, err := repo.Storer.SetEncodedObject(blob)
+		if err != nil {
+			return err
+		}
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: target, Mode: filemode.Regular, Hash: hash})
+	}
+	slices.SortFunc(tree.Entries, func(a, b object.TreeEntry) int { return strings.Compare(a.Name, b.Name) })
+	treeHash, err := store(repo, tree)
+	if err != nil {
+		return err
+	}
+
+	sig, err := signature(repo)
+	if err != nil {
+		return err
+	}
+	commit := &object.Commit{
+		Author:    sig,
+		Committer: sig,
+		Message:   "Notes updated by semver\n",
+		TreeHash:  treeHash,
+	}
+	if !parent.IsZero() {
+		commit.ParentHashes = []plumbing.Hash{parent}
+	}
+	hash, err := store(repo, commit)
+	if err != nil {
+		return err
+	}
+	return repo.Storer.SetReference(plumbing.NewHashReference(ref, hash))
+}
+
+// store writes a tree or commit to the repository.
+func store(repo *gogit.Repository, obj interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	encoded := repo.Storer.NewEncodedObject()
+	if err := obj.Encode(encoded); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(encoded)
+}
+
+func (b *GoGitBackend) Notes(ctx context.Context, ref string) (map[string]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	notes, _, err := readNotes(repo, plumbing.ReferenceName(ref))
+	return notes, err
+}
+
+// readNotes returns the notes under ref and the notes commit holding them,
+// which is zero when ref doesn't exist yet.
+func readNotes(repo *gogit.Repository, ref plumbing.ReferenceName) (map[string]string, plumbing.Hash, error) {
+	notes := make(map[string]string)
+	r, err := repo.Reference(ref, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return notes, plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	commit, err := repo.CommitObject(r.Hash())
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		// Large notes trees fan out into directories named after the
+		// first characters of the hash.
+		notes[strings.ReplaceAll(f.Name, "/", "")] = content
+		return nil
+	})
+	return notes, commit.Hash, err
+}
+
+// signature is the configured committer, now.
+func signature(repo *gogit.Repository) (object.Signature, error) {
+	name, err := configValue(repo, "user.name")
+	if err != nil {
+		return object.Signature{}, err
+	}
+	email, err := configValue(repo, "user.email")
+	if err != nil {
+		return object.Signature{}, err
+	}
+	if name == "" || email == "" {
+		return object.Signature{}, errors.New("user.name and user.email must be configured")
+	}
+	return object.Signature{Name: name, Email: email, When: time.Now()}, nil
+}
+
+func (b *GoGitBackend) CreateBranch(ctx context.Context, name, rev string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	commit, err := commitAt(repo, rev)
+	if err != nil {
+		return err
+	}
+
+	ref := plumbing.NewBranchReferenceName(name)
+	if _, err := repo.Reference(ref, false); err == nil {
+		return fmt.Errorf("a branch named %q already exists", name)
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+	return repo.Storer.SetReference(plumbing.NewHashReference(ref, commit.Hash))
+}
+
+func (b *GoGitBackend) Identity(ctx context.Context) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	return identity(repo)
+}
+
+func identity(repo *gogit.Repository) (string, error) {
+	sig, err := signature(repo)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s <%s>", sig.Name, sig.Email), nil
+}
+
+func (b *GoGitBackend) HooksDir(ctx context.Context) (string, error) {
+	repo, wt, err := b.worktree()
+	if err != nil {
+		return "", err
+	}
+	dir, err := configValue(repo, "core.hooksPath")
+	if err != nil {
+		return "", err
+	}
+	if dir != "" {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(wt.Filesystem.Root(), dir)
+		}
+		return dir, nil
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", errors.New("the repository has no hooks directory")
+	}
+	return filepath.Join(storage.Filesystem().Root(), "hooks"), nil
+}
+
+func (b *GoGitBackend) FileAt(ctx context.Context, rev, path string) ([]byte, error) {
+	repo, wt, err := b.worktree()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := relPaths(wt, []string{path})
+	if err != nil {
+		return nil, err
+	}
+	commit, err := commitAt(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := commit.File(rel[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s: %w", rev, rel[0], err)
+	}
+	content, err := file.Contents()
+	return []byte(content), err
+}
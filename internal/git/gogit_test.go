@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestAddTrailer(t *testing.T) {
+	const signOff = "Signed-off-by: Test User <test@example.com>"
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "subject only",
+			message: "Release 1.0.0",
+			want:    "Release 1.0.0\n\n" + signOff,
+		},
+		{
+			name:    "joins trailers",
+			message: "Release 1.0.0\n\n- Added: x\n\nRelease-Version: 1.0.0",
+			want:    "Release 1.0.0\n\n- Added: x\n\nRelease-Version: 1.0.0\n" + signOff,
+		},
+		{
+			name:    "body is not a trailer block",
+			message: "Release 1.0.0\n\nNote: this is prose, not a trailer.\nSee the changelog",
+			want:    "Release 1.0.0\n\nNote: this is prose, not a trailer.\nSee the changelog\n\n" + signOff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addTrailer(tt.message, signOff); got != tt.want {
+				t.Errorf("addTrailer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoGitBackend_Signing(t *testing.T) {
+	r := newTestRepo(t)
+	b := NewGoGitBackend()
+	r.commit(t, b, "a.go", "first")
+
+	s := New(WithBackend(b), WithSigning(Signing{}))
+	err := s.Tag(context.Background(), &version.Version{Major: 1}, "")
+	if !errors.Is(err, ErrTagFailed) || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Tag() error = %v, want %v and %v", err, ErrTagFailed, errors.ErrUnsupported)
+	}
+	if _, err := s.VerifyTag(context.Background(), "v1.0.0"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("VerifyTag() error = %v, want %v", err, errors.ErrUnsupported)
+	}
+}
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"strings"
+)
+
+// mailmap maps the names and emails commits were recorded with to the
+// canonical ones, as git reads .mailmap. Lines take one of the forms
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+type mailmap struct {
+	entries []mailmapEntry
+}
+
+type mailmapEntry struct {
+	// commitName is empty when the entry applies to any name.
+	commitName  string
+	commitEmail string
+	name        string
+	email       string
+}
+
+func parseMailmap(data string) *mailmap {
+	m := &mailmap{}
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		name1, email1, rest, ok := mailmapPart(line)
+		if !ok {
+			continue
+		}
+		name2, email2, _, ok := mailmapPart(rest)
+		if !ok {
+			// Only the proper name is given.
+			m.entries = append(m.entries, mailmapEntry{commitEmail: email1, name: name1})
+			continue
+		}
+		m.entries = append(m.entries, mailmapEntry{commitName: name2, commitEmail: email2, name: name1, email: email1})
+	}
+	return m
+}
+
+// mailmapPart reads an optional name and the email in angle brackets that
+// follows it.
+func mailmapPart(s string) (name, email, rest string, ok bool) {
+	open := strings.IndexByte(s, '<')
+	if open < 0 {
+		return "", "", "", false
+	}
+	end := strings.IndexByte(s[open:], '>')
+	if end < 0 {
+		return "", "", "", false
+	}
+	return strings.TrimSpace(s[:open]), strings.TrimSpace(s[open+1 : open+end]), s[open+end+1:], true
+}
+
+// resolve returns the canonical name and email of an author. As in git,
+// matching ignores case, entries for both the name and the email win over
+// those for the email alone, and later lines override earlier ones.
+func (m *mailmap) resolve(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+	var byEmail, byName mailmapEntry
+	named := false
+	for _, e := range m.entries {
+		if !strings.EqualFold(e.commitEmail, email) {
+			continue
+		}
+		into := &byEmail
+		if e.commitName != "" {
+			if !strings.EqualFold(e.commitName, name) {
+				continue
+			}
+			into, named = &byName, true
+		}
+		if e.name != "" {
+			into.name = e.name
+		}
+		if e.email != "" {
+			into.email = e.email
+		}
+	}
+	match := byEmail
+	if named {
+		match = byName
+	}
+	if match.name != "" {
+		name = match.name
+	}
+	if match.email != "" {
+		email = match.email
+	}
+	return name, email
+}
@@ -0,0 +1,43 @@
+package git
+
+import "testing"
+
+func TestMailmap_Resolve(t *testing.T) {
+	m := parseMailmap(`# Canonical identities
+Jane Doe <jane@example.com>
+<jane@example.com> <jane@old.example.com>
+Jane Doe <jane@example.com> jdoe <ci@example.com>
+Build Bot <bot@example.com> <ci@example.com>
+Joe <joe@example.com> <JOE@Laptop.local> # trailing comment
+not an entry
+`)
+
+	tests := []struct {
+		name, email         string
+		wantName, wantEmail string
+	}{
+		{"jane", "jane@example.com", "Jane Doe", "jane@example.com"},
+		{"Jane D.", "jane@old.example.com", "Jane D.", "jane@example.com"},
+		{"JDOE", "ci@example.com", "Jane Doe", "jane@example.com"},
+		{"ci", "ci@example.com", "Build Bot", "bot@example.com"},
+		{"joe", "joe@laptop.local", "Joe", "joe@example.com"},
+		{"Someone", "someone@example.com", "Someone", "someone@example.com"},
+	}
+	for _, tt := range tests {
+		if name, email := m.resolve(tt.name, tt.email); name != tt.wantName || email != tt.wantEmail {
+			t.Errorf("resolve(%q, %q) = %q, %q, want %q, %q", tt.name, tt.email, name, email, tt.wantName, tt.wantEmail)
+		}
+	}
+
+	var none *mailmap
+	if name, email := none.resolve("jane", "jane@example.com"); name != "jane" || email != "jane@example.com" {
+		t.Errorf("resolve() without a mailmap = %q, %q", name, email)
+	}
+}
+
+func TestMailmap_LaterLinesOverride(t *testing.T) {
+	m := parseMailmap("Old Name <dev@example.com>\nNew Name <dev@example.com>\n<canonical@example.com> <dev@example.com>\n")
+	if name, email := m.resolve("dev", "dev@example.com"); name != "New Name" || email != "canonical@example.com" {
+		t.Errorf("resolve() = %q, %q, want New Name, canonical@example.com", name, email)
+	}
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Author is the author of a commit, with .mailmap applied.
+type Author struct {
+	Name  string
+	Email string
+}
+
+func (a Author) String() string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+// FileStat is the lines changed in one file. Binary files have no line
+// counts.
+type FileStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+	Binary     bool
+}
+
+// Contributor is an author of commits in a range.
+type Contributor struct {
+	Author
+	Commits int
+	// FirstTime is set when none of the author's commits predate the range.
+	FirstTime bool
+}
+
+// Stats summarise the commits between two releases.
+type Stats struct {
+	Commits int
+	// Contributors are ordered by number of commits, then name. Authors
+	// are told apart by email.
+	Contributors []Contributor
+	Files        []FileStat
+	Insertions   int
+	Deletions    int
+}
+
+// FirstTime returns the contributors whose first commit is in the range.
+func (s *Stats) FirstTime() []Contributor {
+	var first []Contributor
+	for _, c := range s.Contributors {
+		if c.FirstTime {
+			first = append(first, c)
+		}
+	}
+	return first
+}
+
+// Stats summarises the commits reachable from to but not from from. An
+// empty from covers the whole history, in which every author is a first-time
+// contributor.
+func (s *GitService) Stats(ctx context.Context, from, to string) (*Stats, error) {
+	authors, err := s.backend.Authors(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLogFailed, err)
+	}
+	earlier := make(map[string]bool)
+	if from != "" {
+		before, err := s.backend.Authors(ctx, "", from)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrLogFailed, err)
+		}
+		for _, a := range before {
+			earlier[strings.ToLower(a.Email)] = true
+		}
+	}
+
+	stats := &Stats{Commits: len(authors)}
+	index := make(map[string]int)
+	for _, a := range authors {
+		key := strings.ToLower(a.Email)
+		i, ok := index[key]
+		if !ok {
+			// Authors come newest first, so the latest name is kept.
+			i = len(stats.Contributors)
+			index[key] = i
+			stats.Contributors = append(stats.Contributors, Contributor{Author: a, FirstTime: !earlier[key]})
+		}
+		stats.Contributors[i].Commits++
+	}
+	slices.SortStableFunc(stats.Contributors, func(a, b Contributor) int {
+		return cmp.Or(b.Commits-a.Commits, strings.Compare(a.Name, b.Name))
+	})
+
+	if stats.Files, err = s.backend.DiffStat(ctx, from, to); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLogFailed, err)
+	}
+	for _, f := range stats.Files {
+		stats.Insertions += f.Insertions
+		stats.Deletions += f.Deletions
+	}
+	return stats, nil
+}
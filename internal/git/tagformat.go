@@ -0,0 +1,142 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var ErrInvalidTagFormat = errors.New("invalid tag format")
+
+// DefaultTagFormat names release tags v1.2.0 and so on.
+const DefaultTagFormat = "v{{.Version}}"
+
+// versionMark stands in for the version when a template is rendered to find
+// the text around it, and packageMark for a package that isn't configured.
+const (
+	versionMark = "\x00"
+	packageMark = "\x01"
+)
+
+// TagFormat names release tags from a template such as "v{{.Version}}",
+// "release-{{.Version}}" or "{{.Package}}/v{{.Version}}", and recognises the
+// tags it names. Tags that don't fit the template, such as the releases of
+// other components, aren't release tags. A nil *TagFormat is the default
+// format.
+type TagFormat struct {
+	tmpl *template.Template
+	pkg  string
+	// prefix and suffix surround the version in every tag.
+	prefix string
+	suffix string
+}
+
+// tagData is what tag templates receive.
+type tagData struct {
+	Version string
+	Package string
+}
+
+// NewTagFormat parses a tag template, the default one when text is empty.
+// pkg is the .Package the template receives. The template must use
+// .Version exactly once and can't depend on it otherwise, so that tags can
+// be matched back to versions. It can only use .Package when pkg is set, and
+// must make names git accepts as tags.
+func NewTagFormat(text, pkg string) (*TagFormat, error) {
+	if text == "" {
+		text = DefaultTagFormat
+	}
+	tmpl, err := template.New("tag").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTagFormat, err)
+	}
+
+	if pkg == "" {
+		probe := &TagFormat{tmpl: tmpl, pkg: packageMark}
+		if marked, err := probe.render(versionMark); err == nil && strings.Contains(marked, packageMark) {
+			return nil, fmt.Errorf("%w: %q uses {{.Package}} but no tag package is configured", ErrInvalidTagFormat, text)
+		}
+	}
+
+	f := &TagFormat{tmpl: tmpl, pkg: pkg}
+	marked, err := f.render(versionMark)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTagFormat, err)
+	}
+	if strings.Count(marked, versionMark) != 1 {
+		return nil, fmt.Errorf("%w: %q must contain {{.Version}} once", ErrInvalidTagFormat, text)
+	}
+	f.prefix, f.suffix, _ = strings.Cut(marked, versionMark)
+
+	// Check that the version is inserted as is, whatever it is.
+	sample := &version.Version{Major: 1, Minor: 2, Patch: 3}
+	name, err := f.render(sample.String())
+	if err != nil || name != f.prefix+sample.String()+f.suffix {
+		return nil, fmt.Errorf("%w: %q must insert {{.Version}} unchanged", ErrInvalidTagFormat, text)
+	}
+	if !validRefName(name) {
+		return nil, fmt.Errorf("%w: %q makes tags such as %q, which git rejects", ErrInvalidTagFormat, text, name)
+	}
+	return f, nil
+}
+
+// validRefName reports whether git check-ref-format accepts name under
+// refs/tags/.
+func validRefName(name string) bool {
+	if name == "" || name == "@" || strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") || strings.Contains(name, "@{") {
+		return false
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return false
+		}
+	}
+	// Also catches a leading or trailing slash and "//".
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *TagFormat) render(ver string) (string, error) {
+	var b strings.Builder
+	if err := f.tmpl.Execute(&b, tagData{Version: ver, Package: f.pkg}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Name returns the tag of a release.
+func (f *TagFormat) Name(ver *version.Version) string {
+	if f == nil {
+		return "v" + ver.String()
+	}
+	return f.prefix + ver.String() + f.suffix
+}
+
+// Parse returns the version a release tag names. Tags that are not release
+// tags in this format report false.
+func (f *TagFormat) Parse(tag string) (*version.Version, bool) {
+	prefix, suffix := "v", ""
+	if f != nil {
+		prefix, suffix = f.prefix, f.suffix
+	}
+	s, ok := strings.CutPrefix(tag, prefix)
+	if !ok {
+		return nil, false
+	}
+	if s, ok = strings.CutSuffix(s, suffix); !ok {
+		return nil, false
+	}
+	ver, err := version.ParseVersion(s)
+	if err != nil || ver.String() != s {
+		return nil, false
+	}
+	return ver, true
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestNewTagFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		// noPackage leaves the package unset.
+		noPackage bool
+		wantErr   error
+	}{
+		{name: "default", text: ""},
+		{name: "default without a package", text: "", noPackage: true},
+		{name: "prefix", text: "release-{{.Version}}"},
+		{name: "package", text: "{{.Package}}/v{{.Version}}"},
+		{name: "no version", text: "latest", wantErr: ErrInvalidTagFormat},
+		{name: "version twice", text: "v{{.Version}}-{{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "version changed", text: `v{{printf "%.3s" .Version}}`, wantErr: ErrInvalidTagFormat},
+		{name: "version alone", text: "{{.Version}}"},
+		{name: "unknown field", text: "{{.Name}}-{{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "space", text: "release {{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "syntax", text: "v{{.Version", wantErr: ErrInvalidTagFormat},
+		{name: "package not configured", text: "{{.Package}}/v{{.Version}}", noPackage: true, wantErr: ErrInvalidTagFormat},
+		{name: "leading slash", text: "/v{{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "trailing slash", text: "{{.Version}}/", wantErr: ErrInvalidTagFormat},
+		{name: "double slash", text: "{{.Package}}//v{{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "dot dot", text: "v..{{.Version}}", wantErr: ErrInvalidTagFormat},
+		{name: "lock", text: "{{.Version}}.lock", wantErr: ErrInvalidTagFormat},
+		{name: "at brace", text: "{{.Version}}@{x}", wantErr: ErrInvalidTagFormat},
+		{name: "hidden component", text: "{{.Package}}/.v{{.Version}}", wantErr: ErrInvalidTagFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := "api"
+			if tt.noPackage {
+				pkg = ""
+			}
+			if _, err := NewTagFormat(tt.text, pkg); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewTagFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTagFormat(t *testing.T) {
+	ver := &version.Version{Major: 1, Minor: 2, Patch: 3}
+
+	tests := []struct {
+		name     string
+		text     string
+		wantName string
+		// tags maps tags to whether they are release tags of the format.
+		tags map[string]bool
+	}{
+		{
+			name:     "default",
+			wantName: "v1.2.3",
+			tags: map[string]bool{
+				"v0.10.0": true, "1.2.3": false, "v1.2": false, "v1.2.3-rc1": false,
+				"v01.2.3": false, "release": false, "api/v1.2.3": false,
+			},
+		},
+		{
+			name:     "prefix",
+			text:     "release-{{.Version}}",
+			wantName: "release-1.2.3",
+			tags:     map[string]bool{"release-0.1.0": true, "v1.2.3": false, "release-1.2": false},
+		},
+		{
+			name:     "package",
+			text:     "{{.Package}}/v{{.Version}}",
+			wantName: "api/v1.2.3",
+			tags:     map[string]bool{"api/v2.0.0": true, "v1.2.3": false, "web/v1.2.3": false, "api/v1.2.3/x": false},
+		},
+		{
+			name:     "bare version",
+			text:     "{{.Version}}",
+			wantName: "1.2.3",
+			tags:     map[string]bool{"1.0.0": true, "v1.0.0": false, "1.0": false, "release": false, "1.0.0-rc1": false},
+		},
+		{
+			name:     "suffix",
+			text:     "{{.Version}}-{{.Package}}",
+			wantName: "1.2.3-api",
+			tags:     map[string]bool{"1.0.0-api": true, "1.0.0-web": false, "1.0.0": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTagFormat(tt.text, "api")
+			if err != nil {
+				t.Fatalf("NewTagFormat() error = %v", err)
+			}
+			if got := f.Name(ver); got != tt.wantName {
+				t.Errorf("Name() = %q, want %q", got, tt.wantName)
+			}
+			if got, ok := f.Parse(tt.wantName); !ok || got.Compare(ver) != 0 {
+				t.Errorf("Parse(%q) = %v, %v, want %v", tt.wantName, got, ok, ver)
+			}
+			for tag, want := range tt.tags {
+				if _, ok := f.Parse(tag); ok != want {
+					t.Errorf("Parse(%q) ok = %v, want %v", tag, ok, want)
+				}
+			}
+		})
+	}
+
+	var f *TagFormat
+	if got := f.Name(ver); got != "v1.2.3" {
+		t.Errorf("nil Name() = %q, want v1.2.3", got)
+	}
+	if got, ok := f.Parse("v1.2.3"); !ok || got.Compare(ver) != 0 {
+		t.Errorf("nil Parse() = %v, %v", got, ok)
+	}
+}
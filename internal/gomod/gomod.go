@@ -0,0 +1,48 @@
+// Package gomod edits the go.mod file of the module being released.
+package gomod
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+const FileName = "go.mod"
+
+var (
+	ErrInvalidModFile    = errors.New("invalid go.mod")
+	ErrAlreadyRetracted  = errors.New("version already retracted")
+	ErrInvalidModVersion = errors.New("invalid module version")
+)
+
+// Retract adds a retract directive for ver to the go.mod contents data, with
+// the rationale as its comment, so that the go command stops selecting it.
+// ver is a module version such as "v1.2.0".
+func Retract(data []byte, ver, rationale string) ([]byte, error) {
+	if !semver.IsValid(ver) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidModVersion, ver)
+	}
+
+	f, err := modfile.Parse(FileName, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModFile, err)
+	}
+
+	for _, r := range f.Retract {
+		if semver.Compare(r.Low, ver) <= 0 && semver.Compare(ver, r.High) <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAlreadyRetracted, ver)
+		}
+	}
+
+	if err := f.AddRetract(modfile.VersionInterval{Low: ver, High: ver}, rationale); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModFile, err)
+	}
+
+	out, err := f.Format()
+	if err != nil {
+		return nil, fmt.Errorf("formatting go.mod: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package gomod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRetract(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		version string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "adds directive with rationale",
+			content: "module example.com/m\n\ngo 1.23\n",
+			version: "v1.2.0",
+			want:    "module example.com/m\n\ngo 1.23\n\n// Breaks the config loader\nretract v1.2.0\n",
+		},
+		{
+			name:    "already retracted by a range",
+			content: "module example.com/m\n\ngo 1.23\n\nretract [v1.0.0, v1.3.0]\n",
+			version: "v1.2.0",
+			wantErr: ErrAlreadyRetracted,
+		},
+		{
+			name:    "invalid version",
+			content: "module example.com/m\n",
+			version: "1.2.0",
+			wantErr: ErrInvalidModVersion,
+		},
+		{
+			name:    "invalid go.mod",
+			content: "modul example.com/m\n",
+			version: "v1.2.0",
+			wantErr: ErrInvalidModFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Retract([]byte(tt.content), tt.version, "Breaks the config loader")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Retract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("go.mod =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/conventional"
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+var (
+	ErrConflict    = errors.New("cannot install hook")
+	ErrPushRefused = errors.New("push refused")
+)
+
+// Names lists the hooks semver installs.
+var Names = []string{"commit-msg", "pre-push"}
+
+// marker identifies the hooks semver wrote.
+const marker = "# Installed by semver."
+
+// localSuffix is added to the name of a user's hook that a semver hook
+// replaces and runs first.
+const localSuffix = ".local"
+
+// The hook runs the user's hook it replaced, then semver's check. Machines
+// without semver skip the check, so a shared core.hooksPath doesn't block
+// anyone. pre-push gets the refs being pushed on stdin, which both hooks
+// need.
+var script = template.Must(template.New("hook").Parse(`#!/bin/sh
+` + marker + ` Remove it with: semver hooks uninstall
+chained="$0` + localSuffix + `"
+{{- if .Stdin}}
+input=$(cat)
+{{- end}}
+if [ -x "$chained" ]; then
+	{{if .Stdin}}printf '%s\n' "$input" | {{end}}"$chained" "$@" || exit $?
+fi
+if ! command -v semver >/dev/null 2>&1; then
+	echo "semver is not installed; skipping the {{.Name}} check" >&2
+	exit 0
+fi
+{{if .Stdin}}printf '%s\n' "$input" | {{end}}semver hooks run {{.Name}} "$@"
+`))
+
+// Change is a hook installed or removed. Chained reports a user's hook that
+// was kept as <hook>.local when installing, or put back when uninstalling.
+type Change struct {
+	Hook    string
+	Chained bool
+}
+
+func isSemverHook(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Contains(data, []byte(marker)), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Install writes the semver hooks into dir, creating it if needed. A hook
+// semver didn't write is renamed with the .local suffix and run before
+// semver's check; installing again only replaces semver's own hooks.
+func Install(dir string) ([]Change, error) {
+	// Check every hook before touching any.
+	changes := make([]Change, len(Names))
+	for i, name := range Names {
+		changes[i].Hook = name
+		path := filepath.Join(dir, name)
+		if !exists(path) {
+			continue
+		}
+		ours, err := isSemverHook(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		if ours {
+			changes[i].Chained = exists(path + localSuffix)
+			continue
+		}
+		if exists(path + localSuffix) {
+			return nil, fmt.Errorf("%w: both %s and %s exist; merge them into %s first",
+				ErrConflict, name, name+localSuffix, name+localSuffix)
+		}
+		changes[i].Chained = true
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	for _, c := range changes {
+		path := filepath.Join(dir, c.Hook)
+		if ours, _ := isSemverHook(path); !ours && c.Chained {
+			if err := os.Rename(path, path+localSuffix); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+			}
+		}
+
+		var buf bytes.Buffer
+		data := struct {
+			Name  string
+			Stdin bool
+		}{c.Hook, c.Hook == "pre-push"}
+		if err := script.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0755); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+	}
+	return changes, nil
+}
+
+// Uninstall removes the semver hooks from dir and puts back the hooks they
+// chained. Hooks semver didn't write are left alone.
+func Uninstall(dir string) ([]Change, error) {
+	var changes []Change
+	for _, name := range Names {
+		path := filepath.Join(dir, name)
+		if !exists(path) {
+			continue
+		}
+		if ours, err := isSemverHook(path); err != nil || !ours {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return changes, err
+		}
+
+		c := Change{Hook: name}
+		if exists(path + localSuffix) {
+			if err := os.Rename(path+localSuffix, path); err != nil {
+				return changes, err
+			}
+			c.Chained = true
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// exemptPrefixes start the messages git writes itself for merges, reverts
+// and fixups, which aren't Conventional Commits.
+var exemptPrefixes = []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "}
+
+// CheckCommitMsg checks that a message, as git passes it to the commit-msg
+// hook, is a Conventional Commit. Comments and anything below the scissors
+// line are ignored.
+func CheckCommitMsg(message string) error {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	message = strings.TrimSpace(strings.Join(lines, "\n"))
+	// git aborts empty commits itself.
+	if message == "" {
+		return nil
+	}
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return nil
+		}
+	}
+
+	if _, err := conventional.Parse(message); err != nil {
+		return fmt.Errorf(`%w; write "type(scope): description", such as "feat(changelog): add export", with "!" after the type for breaking changes`, err)
+	}
+	return nil
+}
+
+// Repo reads files from the history being pushed.
+type Repo interface {
+	FileAt(ctx context.Context, rev, path string) ([]byte, error)
+}
+
+// PushOptions name the files release tags are checked against, and the
+// format of release tags; nil is the default format.
+type PushOptions struct {
+	VersionFile   string
+	ChangelogFile string
+	Tags          *git.TagFormat
+}
+
+// CheckPush reads the refs being pushed, as git passes them to the
+// pre-push hook, and refuses release tags on commits whose version file
+// holds another version or whose changelog has no section for it.
+func CheckPush(ctx context.Context, repo Repo, opts PushOptions, refs io.Reader) error {
+	var problems []string
+	scanner := bufio.NewScanner(refs)
+	for scanner.Scan() {
+		// <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		rev, remoteRef := fields[1], fields[2]
+		tag, ok := strings.CutPrefix(remoteRef, "refs/tags/")
+		// An all-zero hash deletes the tag.
+		if !ok || strings.Trim(rev, "0") == "" {
+			continue
+		}
+		ver, ok := opts.Tags.Parse(tag)
+		if !ok {
+			continue
+		}
+		problems = append(problems, checkTag(ctx, repo, opts, tag, rev, ver.String())...)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n- %s", ErrPushRefused, strings.Join(problems, "\n- "))
+	}
+	return nil
+}
+
+func checkTag(ctx context.Context, repo Repo, opts PushOptions, tag, rev, ver string) []string {
+	var problems []string
+
+	versionFile := filepath.Base(opts.VersionFile)
+	data, err := repo.FileAt(ctx, rev, opts.VersionFile)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("%s: cannot read %s: %v", tag, versionFile, err))
+	} else if got := strings.TrimSpace(string(data)); got != ver {
+		problems = append(problems, fmt.Sprintf("%s: %s is %q, want %s", tag, versionFile, got, ver))
+	}
+
+	changelogFile := filepath.Base(opts.ChangelogFile)
+	data, err = repo.FileAt(ctx, rev, opts.ChangelogFile)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("%s: cannot read %s: %v", tag, changelogFile, err))
+	} else if _, err := changelog.Notes(data, ver); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: %s has no section for %s", tag, changelogFile, ver))
+	}
+	return problems
+}
@@ -0,0 +1,309 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/conventional"
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+const userHook = "#!/bin/sh\necho \"user hook: $*\" >> \"$(dirname \"$0\")/ran\"\ncat >> \"$(dirname \"$0\")/ran\"\nexit ${USER_HOOK_EXIT:-0}\n"
+
+func writeHook(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readHook(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestInstall(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		want    []Change
+		wantErr error
+	}{
+		{
+			name: "no hooks directory yet",
+			want: []Change{{Hook: "commit-msg"}, {Hook: "pre-push"}},
+		},
+		{
+			name: "existing hook is chained",
+			setup: func(t *testing.T, dir string) {
+				writeHook(t, dir, "pre-push", userHook)
+			},
+			want: []Change{{Hook: "commit-msg"}, {Hook: "pre-push", Chained: true}},
+		},
+		{
+			name: "reinstalling keeps the chain",
+			setup: func(t *testing.T, dir string) {
+				writeHook(t, dir, "pre-push", userHook)
+				if _, err := Install(dir); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []Change{{Hook: "commit-msg"}, {Hook: "pre-push", Chained: true}},
+		},
+		{
+			name: "hook and chained hook both exist",
+			setup: func(t *testing.T, dir string) {
+				writeHook(t, dir, "commit-msg", userHook)
+				writeHook(t, dir, "commit-msg.local", userHook)
+			},
+			wantErr: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "hooks")
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+
+			got, err := Install(dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Install() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if hook := readHook(t, dir, "commit-msg"); hook != userHook {
+					t.Errorf("commit-msg changed on error:\n%s", hook)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Install() = %+v, want %+v", got, tt.want)
+			}
+
+			for _, c := range got {
+				if hook := readHook(t, dir, c.Hook); !strings.Contains(hook, marker) || !strings.Contains(hook, "semver hooks run "+c.Hook) {
+					t.Errorf("%s hook =\n%s", c.Hook, hook)
+				}
+				if c.Chained {
+					if hook := readHook(t, dir, c.Hook+localSuffix); hook != userHook {
+						t.Errorf("%s.local =\n%s\nwant the user's hook", c.Hook, hook)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInstall_Run(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+
+	tests := []struct {
+		name     string
+		hook     string
+		args     []string
+		stdin    string
+		userExit int
+		wantRan  string
+		wantErr  bool
+	}{
+		{
+			name:    "commit-msg",
+			hook:    "commit-msg",
+			args:    []string{".git/COMMIT_EDITMSG"},
+			wantRan: "user hook: .git/COMMIT_EDITMSG\n",
+		},
+		{
+			name:    "pre-push passes the refs on",
+			hook:    "pre-push",
+			args:    []string{"origin", "git@example.com:org/repo.git"},
+			stdin:   "refs/tags/v1.0.0 abc refs/tags/v1.0.0 000\n",
+			wantRan: "user hook: origin git@example.com:org/repo.git\nrefs/tags/v1.0.0 abc refs/tags/v1.0.0 000\n",
+		},
+		{
+			name:     "failing user hook stops",
+			hook:     "commit-msg",
+			args:     []string{"msg"},
+			userExit: 3,
+			wantRan:  "user hook: msg\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeHook(t, dir, tt.hook, userHook)
+			if _, err := Install(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			// semver isn't on this PATH, so only the user's hook runs.
+			cmd := exec.Command(filepath.Join(dir, tt.hook), tt.args...)
+			cmd.Env = []string{"PATH=/usr/bin:/bin", fmt.Sprintf("USER_HOOK_EXIT=%d", tt.userExit)}
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			out, err := cmd.CombinedOutput()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hook error = %v, wantErr %v\n%s", err, tt.wantErr, out)
+			}
+			if !tt.wantErr && !strings.Contains(string(out), "semver is not installed") {
+				t.Errorf("hook output = %q, want the skipped check reported", out)
+			}
+			if ran := readHook(t, dir, "ran"); ran != tt.wantRan {
+				t.Errorf("user hook ran with %q, want %q", ran, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-push", userHook)
+	if _, err := Install(dir); err != nil {
+		t.Fatal(err)
+	}
+	// A hook the user wrote after installing is theirs to keep.
+	writeHook(t, dir, "commit-msg", userHook)
+
+	got, err := Uninstall(dir)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if want := []Change{{Hook: "pre-push", Chained: true}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Uninstall() = %+v, want %+v", got, want)
+	}
+	for _, name := range []string{"commit-msg", "pre-push"} {
+		if hook := readHook(t, dir, name); hook != userHook {
+			t.Errorf("%s =\n%s\nwant the user's hook", name, hook)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-push.local")); !os.IsNotExist(err) {
+		t.Errorf("pre-push.local left behind: %v", err)
+	}
+}
+
+func TestCheckCommitMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantErr error
+	}{
+		{name: "conventional", message: "feat(changelog): add export\n"},
+		{name: "breaking", message: "feat!: drop the v1 config\n\nBREAKING CHANGE: see UPGRADING.md\n"},
+		{name: "release commit", message: "chore(release): v1.2.0\n\n- Added: export\n\nRelease-Version: 1.2.0\n"},
+		{name: "comments are ignored", message: "fix: handle empty files\n# Please enter the commit message\n"},
+		{name: "scissors", message: "docs: fix typo\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n"},
+		{name: "merge", message: "Merge branch 'feature' into main\n"},
+		{name: "revert", message: "Revert \"feat: add export\"\n\nThis reverts commit abc.\n"},
+		{name: "fixup", message: "fixup! feat: add export\n"},
+		{name: "empty", message: "# nothing\n"},
+		{name: "not conventional", message: "Added export\n", wantErr: conventional.ErrNotConventional},
+		{name: "comment hides nothing", message: "# feat: commented out\nupdate stuff\n", wantErr: conventional.ErrNotConventional},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckCommitMsg(tt.message); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckCommitMsg() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeRepo maps "rev:name" to the contents of files by base name.
+type fakeRepo map[string]string
+
+func (r fakeRepo) FileAt(ctx context.Context, rev, path string) ([]byte, error) {
+	content, ok := r[rev+":"+filepath.Base(path)]
+	if !ok {
+		return nil, fmt.Errorf("%s does not exist in %s", path, rev)
+	}
+	return []byte(content), nil
+}
+
+func TestCheckPush(t *testing.T) {
+	const zero = "0000000000000000000000000000000000000000"
+	repo := fakeRepo{
+		"good:VERSION.md":    "1.1.0\n",
+		"good:CHANGELOG.md":  "## [1.1.0] - 2024-12-24\n### Added\n- Export\n",
+		"stale:VERSION.md":   "1.0.0\n",
+		"stale:CHANGELOG.md": "## [1.0.0] - 2024-12-01\n### Added\n- Import\n",
+	}
+	opts := PushOptions{VersionFile: "/repo/VERSION.md", ChangelogFile: "/repo/CHANGELOG.md"}
+
+	tests := []struct {
+		name     string
+		format   string
+		refs     string
+		wantErr  error
+		wantMsgs []string
+	}{
+		{
+			name: "matching release tag",
+			refs: "refs/tags/v1.1.0 good refs/tags/v1.1.0 " + zero + "\n",
+		},
+		{
+			name: "branches and other tags are ignored",
+			refs: "refs/heads/main stale refs/heads/main " + zero + "\nrefs/tags/nightly stale refs/tags/nightly " + zero + "\n",
+		},
+		{
+			name: "deleting a tag",
+			refs: "(delete) " + zero + " refs/tags/v9.0.0 stale\n",
+		},
+		{
+			name:     "tag doesn't match the files",
+			refs:     "refs/tags/v1.1.0 stale refs/tags/v1.1.0 " + zero + "\n",
+			wantErr:  ErrPushRefused,
+			wantMsgs: []string{`v1.1.0: VERSION.md is "1.0.0", want 1.1.0`, "v1.1.0: CHANGELOG.md has no section for 1.1.0"},
+		},
+		{
+			name:     "component tags",
+			format:   "{{.Package}}/v{{.Version}}",
+			refs:     "refs/tags/v1.1.0 stale refs/tags/v1.1.0 " + zero + "\nrefs/tags/api/v1.1.0 stale refs/tags/api/v1.1.0 " + zero + "\n",
+			wantErr:  ErrPushRefused,
+			wantMsgs: []string{`api/v1.1.0: VERSION.md is "1.0.0", want 1.1.0`},
+		},
+		{
+			name:     "files missing",
+			refs:     "refs/tags/v2.0.0 missing refs/tags/v2.0.0 " + zero + "\n",
+			wantErr:  ErrPushRefused,
+			wantMsgs: []string{"v2.0.0: cannot read VERSION.md", "v2.0.0: cannot read CHANGELOG.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := git.NewTagFormat(tt.format, "api")
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := opts
+			opts.Tags = tags
+			err = CheckPush(context.Background(), repo, opts, strings.NewReader(tt.refs))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckPush() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, msg := range tt.wantMsgs {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("CheckPush() error = %v, want it to mention %q", err, msg)
+				}
+			}
+		})
+	}
+}
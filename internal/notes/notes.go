@@ -0,0 +1,150 @@
+// Package notes records structured release metadata as git notes on release
+// commits, so that tooling can query releases without parsing the
+// changelog.
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var ErrInvalidNote = errors.New("invalid release note")
+
+// Ref holds the release notes. Like any notes ref it isn't pushed or
+// fetched unless asked for, e.g. git push origin refs/notes/semver.
+const Ref = "refs/notes/semver"
+
+// SchemaVersion is bumped whenever the note format changes incompatibly.
+const SchemaVersion = 1
+
+// Release is the note attached to a release commit.
+type Release struct {
+	Schema int `json:"schema"`
+	// Commit is the release commit. It is filled in when notes are read,
+	// as notes are filed under the commit they describe.
+	Commit          string       `json:"commit,omitempty"`
+	Version         string       `json:"version"`
+	PreviousVersion string       `json:"previousVersion,omitempty"`
+	Type            version.Type `json:"type"`
+	Entries         []Entry      `json:"entries"`
+	ToolVersion     string       `json:"toolVersion"`
+	ReleasedBy      string       `json:"releasedBy"`
+	ReleasedAt      time.Time    `json:"releasedAt"`
+}
+
+type Entry struct {
+	Category    changelog.Category `json:"category"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description"`
+	Author      string             `json:"author,omitempty"`
+	Commit      string             `json:"commit,omitempty"`
+	Refs        []string           `json:"refs,omitempty"`
+}
+
+// Entries converts changelog entries for a note.
+func Entries(entries []changelog.Entry) []Entry {
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, Entry{
+			Category:    e.Category,
+			Summary:     e.Short,
+			Description: e.Long,
+			Author:      e.Author,
+			Commit:      e.Commit,
+			Refs:        e.Refs,
+		})
+	}
+	return out
+}
+
+// ToolVersion is the version of semver itself, "(devel)" when it wasn't
+// installed from a release.
+func ToolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+// Marshal encodes the note as indented JSON.
+func (r *Release) Marshal() (string, error) {
+	stored := *r
+	stored.Commit = ""
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// Parse decodes a note, refusing notes of a newer schema than this version
+// of semver understands.
+func Parse(note string) (*Release, error) {
+	var r Release
+	if err := json.Unmarshal([]byte(note), &r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNote, err)
+	}
+	if r.Schema < 1 || r.Schema > SchemaVersion {
+		return nil, fmt.Errorf("%w: unsupported schema %d", ErrInvalidNote, r.Schema)
+	}
+	if _, err := version.ParseVersion(r.Version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNote, err)
+	}
+	return &r, nil
+}
+
+// Repo is where notes are kept.
+type Repo interface {
+	AddNote(ctx context.Context, ref, rev, note string) error
+	Notes(ctx context.Context, ref string) (map[string]string, error)
+}
+
+// Add attaches r to the commit rev points at, replacing its note.
+func Add(ctx context.Context, repo Repo, rev string, r *Release) error {
+	note, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+	return repo.AddNote(ctx, Ref, rev, note)
+}
+
+// List returns every release note, newest version first. Notes that aren't
+// release metadata, such as ones written by hand under Ref, are skipped and
+// reported in invalid, one ErrInvalidNote per commit.
+func List(ctx context.Context, repo Repo) (releases []*Release, invalid []error, err error) {
+	notes, err := repo.Notes(ctx, Ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	commits := slices.Sorted(maps.Keys(notes))
+	releases = make([]*Release, 0, len(notes))
+	for _, commit := range commits {
+		r, err := Parse(notes[commit])
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("note on %s: %w", commit, err))
+			continue
+		}
+		r.Commit = commit
+		releases = append(releases, r)
+	}
+	slices.SortFunc(releases, func(a, b *Release) int {
+		// Versions were checked by Parse.
+		va, _ := version.ParseVersion(a.Version)
+		vb, _ := version.ParseVersion(b.Version)
+		if c := vb.Compare(va); c != 0 {
+			return c
+		}
+		return b.ReleasedAt.Compare(a.ReleasedAt)
+	})
+	return releases, invalid, nil
+}
@@ -0,0 +1,167 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var errTest = errors.New("test error")
+
+type fakeRepo struct {
+	notes map[string]string
+	err   error
+}
+
+func (f *fakeRepo) AddNote(ctx context.Context, ref, rev, note string) error {
+	if ref != Ref {
+		return errors.New("wrong ref " + ref)
+	}
+	if f.notes == nil {
+		f.notes = make(map[string]string)
+	}
+	f.notes[rev] = note
+	return f.err
+}
+
+func (f *fakeRepo) Notes(ctx context.Context, ref string) (map[string]string, error) {
+	if ref != Ref {
+		return nil, errors.New("wrong ref " + ref)
+	}
+	return f.notes, f.err
+}
+
+func release(ver, prev string, at time.Time) *Release {
+	return &Release{
+		Schema:          SchemaVersion,
+		Version:         ver,
+		PreviousVersion: prev,
+		Type:            version.Minor,
+		Entries:         []Entry{{Category: changelog.Added, Summary: "Export", Commit: "abc1234", Refs: []string{"#12"}}},
+		ToolVersion:     "v1.0.0",
+		ReleasedBy:      "Test User <test@example.com>",
+		ReleasedAt:      at,
+	}
+}
+
+func TestRelease_MarshalParse(t *testing.T) {
+	r := release("1.2.0", "1.1.0", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r.Commit = "0123abc"
+
+	note, err := r.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(note, "0123abc") {
+		t.Errorf("Marshal() stored the commit:\n%s", note)
+	}
+	for _, want := range []string{`"version": "1.2.0"`, `"previousVersion": "1.1.0"`, `"type": "minor"`, `"releasedAt": "2024-03-01T12:00:00Z"`, `"category": "Added"`} {
+		if !strings.Contains(note, want) {
+			t.Errorf("Marshal() = %s, want it to contain %s", note, want)
+		}
+	}
+
+	got, err := Parse(note)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	r.Commit = ""
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("Parse() = %+v, want %+v", got, r)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		note    string
+		wantErr error
+	}{
+		{name: "valid", note: `{"schema": 1, "version": "1.0.0"}`},
+		{name: "not json", note: "Reviewed-by: someone", wantErr: ErrInvalidNote},
+		{name: "no schema", note: `{"version": "1.0.0"}`, wantErr: ErrInvalidNote},
+		{name: "newer schema", note: `{"schema": 2, "version": "1.0.0"}`, wantErr: ErrInvalidNote},
+		{name: "invalid version", note: `{"schema": 1, "version": "next"}`, wantErr: ErrInvalidNote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.note); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{}
+	for commit, r := range map[string]*Release{
+		"c1": release("1.0.0", "", at),
+		"c3": release("1.10.0", "1.2.0", at.Add(2*time.Hour)),
+		"c2": release("1.2.0", "1.0.0", at.Add(time.Hour)),
+	} {
+		if err := Add(ctx, repo, commit, r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	got, invalid, err := List(ctx, repo)
+	if err != nil || invalid != nil {
+		t.Fatalf("List() error = %v, invalid %v", err, invalid)
+	}
+	var order []string
+	for _, r := range got {
+		order = append(order, r.Commit+" "+r.Version)
+	}
+	if want := []string{"c3 1.10.0", "c2 1.2.0", "c1 1.0.0"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("List() = %q, want %q", order, want)
+	}
+
+	repo.notes["c4"] = "free-form note"
+	got, invalid, err = List(ctx, repo)
+	if err != nil {
+		t.Fatalf("List() with a foreign note error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("List() with a foreign note returned %d releases, want 3", len(got))
+	}
+	if len(invalid) != 1 || !errors.Is(invalid[0], ErrInvalidNote) || !strings.Contains(invalid[0].Error(), "c4") {
+		t.Errorf("List() invalid = %v, want ErrInvalidNote on c4", invalid)
+	}
+
+	repo.err = errTest
+	if _, _, err := List(ctx, repo); !errors.Is(err, errTest) {
+		t.Errorf("List() error = %v, want %v", err, errTest)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	got := Entries([]changelog.Entry{{
+		Category: changelog.Fixed,
+		Short:    "Crash on start",
+		Long:     "When the config is empty.",
+		Author:   "@dev",
+		Commit:   "abc1234",
+		Refs:     []string{"#7"},
+		Line:     12,
+	}})
+	want := []Entry{{
+		Category:    changelog.Fixed,
+		Summary:     "Crash on start",
+		Description: "When the config is empty.",
+		Author:      "@dev",
+		Commit:      "abc1234",
+		Refs:        []string{"#7"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+// Package preflight checks that a repository is ready for a release before
+// any file is modified.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrFailed       = errors.New("preflight checks failed")
+	ErrUnknownCheck = errors.New("unknown preflight check")
+)
+
+// Check names a preflight check, as accepted by --skip-check.
+type Check string
+
+const (
+	CheckClean    Check = "clean"
+	CheckBranch   Check = "branch"
+	CheckUpstream Check = "upstream"
+	CheckTag      Check = "tag"
+	CheckIdentity Check = "identity"
+)
+
+// Checks lists every check in the order they run.
+var Checks = []Check{CheckClean, CheckBranch, CheckUpstream, CheckTag, CheckIdentity}
+
+// Repo is the repository state the checks read.
+type Repo interface {
+	Changes(ctx context.Context, exclude []string) ([]string, error)
+	Unstaged(ctx context.Context, exclude []string) ([]string, error)
+	Branch(ctx context.Context) (string, error)
+	Behind(ctx context.Context) (string, int, error)
+	TagExists(ctx context.Context, tag string) (bool, error)
+	Identity(ctx context.Context) (string, error)
+}
+
+type Options struct {
+	// ReleaseFiles may have changes; the release writes them anyway.
+	ReleaseFiles []string
+	// Branches are the path.Match patterns of branches releases may be
+	// made from, such as "main" or "release/*". Any branch is allowed when
+	// empty.
+	Branches []string
+	// Tag is the tag the release will create.
+	Tag string
+	// IncludeStaged lets staged changes through, as the release commits
+	// them; only changes that aren't staged fail the clean check.
+	IncludeStaged bool
+	Skip          []Check
+}
+
+// Failure is a failed check with a hint on how to resolve it.
+type Failure struct {
+	Check   Check
+	Problem string
+	Hint    string
+}
+
+// Error lists the failed checks.
+type Error struct {
+	Failures []Failure
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrFailed.Error() + ":")
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n  - %s\n    %s, or skip this check with --skip-check %s", f.Problem, f.Hint, f.Check)
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return ErrFailed
+}
+
+// Run runs every check not skipped and returns an *Error listing the
+// failures, if any. A check that can't run at all aborts with its error.
+func Run(ctx context.Context, repo Repo, opts Options) error {
+	for _, c := range opts.Skip {
+		if !slices.Contains(Checks, c) {
+			return fmt.Errorf("%w: %s", ErrUnknownCheck, c)
+		}
+	}
+
+	var failures []Failure
+	for _, c := range Checks {
+		if slices.Contains(opts.Skip, c) {
+			continue
+		}
+		f, err := run(ctx, repo, opts, c)
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", c, err)
+		}
+		if f != nil {
+			failures = append(failures, *f)
+		}
+	}
+
+	if len(failures) > 0 {
+		return &Error{Failures: failures}
+	}
+	return nil
+}
+
+func run(ctx context.Context, repo Repo, opts Options, c Check) (*Failure, error) {
+	switch c {
+	case CheckClean:
+		list, hint := repo.Changes, "commit or stash them first"
+		if opts.IncludeStaged {
+			list, hint = repo.Unstaged, "stage them to include them in the release, or commit or stash them first"
+		}
+		changes, err := list(ctx, opts.ReleaseFiles)
+		if err != nil || len(changes) == 0 {
+			return nil, err
+		}
+		return &Failure{
+			Check:   c,
+			Problem: "working tree has uncommitted changes: " + strings.Join(changes, ", "),
+			Hint:    hint,
+		}, nil
+
+	case CheckBranch:
+		if len(opts.Branches) == 0 {
+			return nil, nil
+		}
+		branch, err := repo.Branch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if branch == "" {
+			return &Failure{
+				Check:   c,
+				Problem: "HEAD is detached",
+				Hint:    "check out one of the release branches (" + strings.Join(opts.Branches, ", ") + ")",
+			}, nil
+		}
+		for _, pattern := range opts.Branches {
+			if ok, _ := path.Match(pattern, branch); ok {
+				return nil, nil
+			}
+		}
+		return &Failure{
+			Check:   c,
+			Problem: fmt.Sprintf("branch %s is not a release branch", branch),
+			Hint:    "check out one of " + strings.Join(opts.Branches, ", ") + " or add it to preflight.branches",
+		}, nil
+
+	case CheckUpstream:
+		upstream, behind, err := repo.Behind(ctx)
+		if err != nil || behind == 0 {
+			return nil, err
+		}
+		return &Failure{
+			Check:   c,
+			Problem: fmt.Sprintf("branch is %d commit(s) behind %s", behind, upstream),
+			Hint:    "pull them with git pull --rebase",
+		}, nil
+
+	case CheckTag:
+		if opts.Tag == "" {
+			return nil, nil
+		}
+		exists, err := repo.TagExists(ctx, opts.Tag)
+		if err != nil || !exists {
+			return nil, err
+		}
+		return &Failure{
+			Check:   c,
+			Problem: fmt.Sprintf("tag %s already exists", opts.Tag),
+			Hint:    "choose another bump type or delete the tag with git tag -d " + opts.Tag,
+		}, nil
+
+	case CheckIdentity:
+		if _, err := repo.Identity(ctx); err != nil {
+			return &Failure{
+				Check:   c,
+				Problem: "no git identity is configured",
+				Hint:    `set one with git config user.name "Your Name" and git config user.email you@example.com`,
+			}, nil
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeRepo struct {
+	// changes lists every changed path and unstaged those of them with
+	// changes that aren't staged.
+	changes  []string
+	unstaged []string
+	branch   string
+	upstream string
+	behind   int
+	tags     []string
+	identErr error
+	// excluded records the paths passed to Changes.
+	excluded []string
+}
+
+func (r *fakeRepo) Changes(ctx context.Context, exclude []string) ([]string, error) {
+	r.excluded = exclude
+	return r.changes, nil
+}
+
+func (r *fakeRepo) Unstaged(ctx context.Context, exclude []string) ([]string, error) {
+	r.excluded = exclude
+	return r.unstaged, nil
+}
+
+func (r *fakeRepo) Branch(context.Context) (string, error) { return r.branch, nil }
+
+func (r *fakeRepo) Behind(context.Context) (string, int, error) { return r.upstream, r.behind, nil }
+
+func (r *fakeRepo) TagExists(ctx context.Context, tag string) (bool, error) {
+	for _, t := range r.tags {
+		if t == tag {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRepo) Identity(context.Context) (string, error) {
+	return "Test User <test@example.com>", r.identErr
+}
+
+func TestRun(t *testing.T) {
+	ready := func() *fakeRepo {
+		return &fakeRepo{branch: "main", upstream: "origin/main", tags: []string{"v1.0.0"}}
+	}
+	opts := Options{
+		ReleaseFiles: []string{"VERSION.md", "CHANGELOG.md"},
+		Branches:     []string{"main", "release/*"},
+		Tag:          "v1.1.0",
+	}
+
+	tests := []struct {
+		name    string
+		repo    func(r *fakeRepo)
+		opts    func(o *Options)
+		want    []Check
+		wantErr error
+	}{
+		{name: "ready"},
+		{
+			name: "release branch pattern",
+			repo: func(r *fakeRepo) { r.branch = "release/1.4" },
+		},
+		{
+			name: "any branch when none are configured",
+			repo: func(r *fakeRepo) { r.branch = "" },
+			opts: func(o *Options) { o.Branches = nil },
+		},
+		{
+			name: "every check fails",
+			repo: func(r *fakeRepo) {
+				r.changes = []string{"main.go"}
+				r.branch = "feature/x"
+				r.behind = 2
+				r.tags = append(r.tags, "v1.1.0")
+				r.identErr = errors.New("no email")
+			},
+			want:    Checks,
+			wantErr: ErrFailed,
+		},
+		{
+			name: "staged changes included",
+			repo: func(r *fakeRepo) { r.changes = []string{"other.txt"} },
+			opts: func(o *Options) { o.IncludeStaged = true },
+		},
+		{
+			name: "unstaged changes with staged ones included",
+			repo: func(r *fakeRepo) {
+				r.changes = []string{"main.go", "other.txt"}
+				r.unstaged = []string{"main.go"}
+			},
+			opts:    func(o *Options) { o.IncludeStaged = true },
+			want:    []Check{CheckClean},
+			wantErr: ErrFailed,
+		},
+		{
+			name:    "detached HEAD",
+			repo:    func(r *fakeRepo) { r.branch = "" },
+			want:    []Check{CheckBranch},
+			wantErr: ErrFailed,
+		},
+		{
+			name: "skipped checks",
+			repo: func(r *fakeRepo) {
+				r.changes = []string{"main.go"}
+				r.tags = append(r.tags, "v1.1.0")
+			},
+			opts: func(o *Options) { o.Skip = []Check{CheckClean, CheckTag} },
+		},
+		{
+			name:    "unknown check",
+			opts:    func(o *Options) { o.Skip = []Check{"lint"} },
+			wantErr: ErrUnknownCheck,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := ready()
+			if tt.repo != nil {
+				tt.repo(repo)
+			}
+			o := opts
+			if tt.opts != nil {
+				tt.opts(&o)
+			}
+
+			err := Run(context.Background(), repo, o)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var failed []Check
+			var pfErr *Error
+			if errors.As(err, &pfErr) {
+				for _, f := range pfErr.Failures {
+					failed = append(failed, f.Check)
+					if f.Hint == "" || !strings.Contains(err.Error(), "--skip-check "+string(f.Check)) {
+						t.Errorf("failure %+v has no remediation hint in %q", f, err)
+					}
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.want) {
+				t.Errorf("failed checks = %v, want %v", failed, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_ExcludesReleaseFiles(t *testing.T) {
+	repo := &fakeRepo{}
+	files := []string{"VERSION.md", "CHANGELOG.md"}
+	if err := Run(context.Background(), repo, Options{ReleaseFiles: files}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(repo.excluded, files) {
+		t.Errorf("excluded = %v, want %v", repo.excluded, files)
+	}
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/conventional"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// commitEntries generates changelog entries from the commits made since the
+// latest release tag.
+func (a *App) commitEntries(ctx context.Context) ([]changelog.Entry, error) {
+	tag, err := a.git.LatestTag(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding latest tag: %w", err)
+	}
+
+	commits, err := a.git.Log(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("reading commits: %w", err)
+	}
+
+	return entriesFromCommits(commits, a.cfg.Changelog.Commits, a.refs.Keys()), nil
+}
+
+// recommendBump works out the bump the commits since the latest release
+// tag call for, along with the headers of the commits that call for it. It
+// returns "" when there is no recommendation.
+func (a *App) recommendBump(ctx context.Context) (version.Type, []string) {
+	tag, err := a.git.LatestTag(ctx)
+	if err != nil {
+		a.logger.Debug("no bump recommendation", "error", err)
+		return "", nil
+	}
+	commits, err := a.git.Log(ctx, tag)
+	if err != nil {
+		a.logger.Debug("no bump recommendation", "error", err)
+		return "", nil
+	}
+	cur, err := a.version.Read()
+	if err != nil {
+		a.logger.Debug("no bump recommendation", "error", err)
+		return "", nil
+	}
+
+	return Recommend(cur, commits, a.cfg.Changelog.Commits)
+}
+
+// Recommend returns the largest bump commits call for on top of cur, with
+// the headers of the commits that call for it, or "" when none does. Only
+// commits the changelog lists count: merges when they are included, and
+// excluded types only when they are breaking.
+func Recommend(cur *version.Version, commits []git.Commit, cfg config.CommitsConfig) (version.Type, []string) {
+	var messages []string
+	for _, c := range commits {
+		if c.Merge && !cfg.IncludeMerges {
+			continue
+		}
+		if cc, err := conventional.Parse(c.Message); err == nil && slices.Contains(cfg.ExcludeTypes, cc.Type) && !cc.Breaking {
+			continue
+		}
+		messages = append(messages, c.Message)
+	}
+	return conventional.Recommend(cur, messages)
+}
+
+// refTrailers are the commit trailers that carry issue references.
+var refTrailers = []string{"refs", "ref", "fixes", "fix", "closes", "close", "resolves", "issue", "issues", "pr", "see-also"}
+
+// trailerRefs collects the references in the reference trailers. A
+// trailer written as "Fixes #123" is parsed with the number as its value.
+func trailerRefs(footers []conventional.Footer) []string {
+	var refs []string
+	for _, f := range footers {
+		if !slices.Contains(refTrailers, strings.ToLower(f.Token)) {
+			continue
+		}
+		value := f.Value
+		if _, err := strconv.Atoi(value); err == nil {
+			value = "#" + value
+		}
+		refs = changelog.MergeRefs(refs, changelog.ExtractRefs(value, nil)...)
+	}
+	return refs
+}
+
+// entriesFromCommits turns commits into entries. Conventional Commits are
+// categorized by type; anything else is listed as Changed. Merge commits
+// and excluded types are dropped, except for breaking changes, which the
+// changelog must always explain. References to the given tracker keys
+// and issue numbers are taken from the message and its trailers.
+func entriesFromCommits(commits []git.Commit, cfg config.CommitsConfig, keys []string) []changelog.Entry {
+	var entries []changelog.Entry
+	for _, c := range commits {
+		if c.Merge && !cfg.IncludeMerges {
+			continue
+		}
+
+		e := changelog.Entry{Author: git.Handle(c.Author, c.Email), Commit: c.Hash}
+
+		cc, err := conventional.Parse(c.Message)
+		if err != nil {
+			subject, body, _ := strings.Cut(c.Message, "\n")
+			e.Category = changelog.Changed
+			e.Short, e.Refs = changelog.SplitRefs(strings.TrimSpace(subject), keys)
+			e.Long = strings.TrimSpace(body)
+			e.Refs = changelog.MergeRefs(e.Refs, changelog.ExtractRefs(e.Long, keys)...)
+			entries = append(entries, e)
+			continue
+		}
+
+		if slices.Contains(cfg.ExcludeTypes, cc.Type) && !cc.Breaking {
+			continue
+		}
+
+		e.Category = changelog.CommitCategory(cc.Type)
+		e.Short, e.Refs = changelog.SplitRefs(cc.Description, keys)
+		e.Refs = changelog.MergeRefs(e.Refs, changelog.ExtractRefs(cc.Body, keys)...)
+		e.Refs = changelog.MergeRefs(e.Refs, trailerRefs(cc.Footers)...)
+		if cc.Scope != "" {
+			e.Short = fmt.Sprintf("**%s:** %s", cc.Scope, e.Short)
+		}
+		e.Long = cc.Body
+		if cc.Breaking {
+			e.Short = "**BREAKING:** " + e.Short
+			if cc.BreakingNote != "" {
+				e.Long = strings.TrimSpace(e.Long + "\n\n" + cc.BreakingNote)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var testCommits = []git.Commit{
+	{Hash: "a1", Author: "Alice", Message: "feat(api): add endpoint\n\nWith details."},
+	{Hash: "b2", Author: "Bob", Message: "Merge branch 'topic'", Merge: true},
+	{Hash: "c3", Author: "Carol", Message: "chore: bump deps"},
+	{Hash: "d4", Author: "Dan", Message: "fix!: reject bad input\n\nBREAKING CHANGE: empty input is an error"},
+	{Hash: "e5", Author: "Eve", Message: "Tidy up\n\nfree-form body"},
+}
+
+func TestEntriesFromCommits_Refs(t *testing.T) {
+	commits := []git.Commit{
+		{
+			Hash: "a1", Author: "Alice Smith", Email: "1234+alice@users.noreply.github.com",
+			Message: "fix(loader): fix race in loader (#123)\n\nRefs: PROJ-42, OTHER-1",
+		},
+		{Hash: "b2", Author: "Bob", Email: "bob@example.com", Message: "feat: add export\n\nCloses #9"},
+		{Hash: "c3", Author: "Carol", Email: "carol@example.com", Message: "Handle UTF-8 names\n\nSee PROJ-7 and #8."},
+	}
+
+	want := []changelog.Entry{
+		{Category: changelog.Fixed, Short: "**loader:** fix race in loader", Author: "@alice", Commit: "a1", Refs: []string{"#123", "PROJ-42", "OTHER-1"}},
+		{Category: changelog.Added, Short: "add export", Author: "Bob", Commit: "b2", Refs: []string{"#9"}},
+		{Category: changelog.Changed, Short: "Handle UTF-8 names", Long: "See PROJ-7 and #8.", Author: "Carol", Commit: "c3", Refs: []string{"#8", "PROJ-7"}},
+	}
+
+	got := entriesFromCommits(commits, config.CommitsConfig{}, []string{"PROJ"})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entriesFromCommits() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestEntriesFromCommits_BreakingNeverExcluded(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a1", Author: "Alice", Message: "chore!: drop support for Go 1.21"},
+		{Hash: "b2", Author: "Bob", Message: "build: switch to the new toolchain\n\nBREAKING CHANGE: Go 1.23 is required"},
+		{Hash: "c3", Author: "Carol", Message: "chore: tidy up"},
+	}
+	want := []changelog.Entry{
+		{Category: changelog.Changed, Short: "**BREAKING:** drop support for Go 1.21", Author: "Alice", Commit: "a1"},
+		{Category: changelog.Changed, Short: "**BREAKING:** switch to the new toolchain", Long: "Go 1.23 is required", Author: "Bob", Commit: "b2"},
+	}
+
+	cfg := config.CommitsConfig{ExcludeTypes: []string{"chore", "ci", "build", "test", "style"}}
+	if got := entriesFromCommits(commits, cfg, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("entriesFromCommits() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestEntriesFromCommits(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.CommitsConfig
+		want []changelog.Entry
+	}{
+		{
+			name: "merges and excluded types are dropped",
+			cfg:  config.CommitsConfig{ExcludeTypes: []string{"chore"}},
+			want: []changelog.Entry{
+				{Category: changelog.Added, Short: "**api:** add endpoint", Long: "With details.", Author: "Alice", Commit: "a1"},
+				{Category: changelog.Fixed, Short: "**BREAKING:** reject bad input", Long: "empty input is an error", Author: "Dan", Commit: "d4"},
+				{Category: changelog.Changed, Short: "Tidy up", Long: "free-form body", Author: "Eve", Commit: "e5"},
+			},
+		},
+		{
+			name: "everything included",
+			cfg:  config.CommitsConfig{IncludeMerges: true},
+			want: []changelog.Entry{
+				{Category: changelog.Added, Short: "**api:** add endpoint", Long: "With details.", Author: "Alice", Commit: "a1"},
+				{Category: changelog.Changed, Short: "Merge branch 'topic'", Author: "Bob", Commit: "b2"},
+				{Category: changelog.Changed, Short: "bump deps", Author: "Carol", Commit: "c3"},
+				{Category: changelog.Fixed, Short: "**BREAKING:** reject bad input", Long: "empty input is an error", Author: "Dan", Commit: "d4"},
+				{Category: changelog.Changed, Short: "Tidy up", Long: "free-form body", Author: "Eve", Commit: "e5"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := entriesFromCommits(testCommits, tt.cfg, nil)
+			if len(got) != len(tt.want) {
+				t.Fatalf("entriesFromCommits() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("entry %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModel_FromCommits(t *testing.T) {
+	tests := []struct {
+		name        string
+		fromCommits bool
+		commits     []git.Commit
+		logErr      error
+		wantState   state
+		wantEntries int
+	}{
+		{
+			name:        "entries are pre-filled",
+			fromCommits: true,
+			commits:     testCommits,
+			wantState:   stateEntries,
+			wantEntries: 3,
+		},
+		{
+			name:        "no commits falls back to manual entry",
+			fromCommits: true,
+			wantState:   stateShortDesc,
+		},
+		{
+			name:        "log errors fall back to manual entry",
+			fromCommits: true,
+			commits:     testCommits,
+			logErr:      errTest,
+			wantState:   stateShortDesc,
+		},
+		{
+			name:      "disabled",
+			commits:   testCommits,
+			wantState: stateShortDesc,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Changelog.FromCommits = tt.fromCommits
+			cfg.Changelog.Commits.ExcludeTypes = []string{"chore"}
+
+			app := &App{
+				cfg:     cfg,
+				logger:  slog.Default(),
+				version: &mockVersionService{version: &version.Version{Major: 1, Minor: 0, Patch: 0}},
+				git:     &mockGitService{latestTag: "v1.0.0", commits: tt.commits, logErr: tt.logErr},
+				log:     &mockChangelogService{},
+			}
+
+			m := send(initialModel(context.Background(), app), "enter")
+			if m.state != tt.wantState {
+				t.Errorf("state = %v, want %v", m.state, tt.wantState)
+			}
+			if len(m.entries) != tt.wantEntries {
+				t.Errorf("len(entries) = %d, want %d", len(m.entries), tt.wantEntries)
+			}
+		})
+	}
+}
+
+func TestModel_RecommendedBump(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    *version.Version
+		line       *version.Line
+		exclude    []string
+		commits    []git.Commit
+		logErr     error
+		wantCursor int
+		wantView   []string
+	}{
+		{
+			name:       "breaking change",
+			current:    &version.Version{Major: 1},
+			commits:    testCommits,
+			wantCursor: 0,
+			wantView:   []string{"major (recommended)", "  - fix!: reject bad input\n"},
+		},
+		{
+			name:       "breaking change before 1.0.0",
+			current:    &version.Version{Minor: 4},
+			commits:    testCommits,
+			wantCursor: 1,
+			wantView:   []string{"minor (recommended)", "  - fix!: reject bad input\n"},
+		},
+		{
+			name:       "merges are not counted",
+			current:    &version.Version{Major: 1},
+			commits:    []git.Commit{{Message: "fix: a"}, {Message: "feat!: merged", Merge: true}},
+			wantCursor: 2,
+			wantView:   []string{"patch (recommended)", "  - fix: a\n"},
+		},
+		{
+			name:       "excluded types are not counted",
+			current:    &version.Version{Major: 1},
+			exclude:    []string{"perf"},
+			commits:    []git.Commit{{Message: "perf: cache lookups"}, {Message: "docs: a"}},
+			wantCursor: 0,
+		},
+		{
+			name:       "excluded breaking changes are counted",
+			current:    &version.Version{Major: 1},
+			exclude:    []string{"refactor"},
+			commits:    []git.Commit{{Message: "fix: a"}, {Message: "refactor!: drop the v1 API"}},
+			wantCursor: 0,
+			wantView:   []string{"major (recommended)", "  - refactor!: drop the v1 API\n"},
+		},
+		{
+			name:       "maintenance line",
+			current:    &version.Version{Major: 1, Minor: 4, Patch: 2},
+			line:       &version.Line{Major: 1, Minor: 4},
+			commits:    []git.Commit{{Message: "fix: a"}},
+			wantCursor: 0,
+			wantView:   []string{"release/1.4 only takes patch releases", "> patch (recommended)\n\n"},
+		},
+		{
+			name:       "maintenance line with a feature",
+			current:    &version.Version{Major: 1, Minor: 4, Patch: 2},
+			line:       &version.Line{Major: 1, Minor: 4},
+			commits:    []git.Commit{{Message: "fix: a"}, {Message: "feat: b"}},
+			wantCursor: 0,
+			wantView:   []string{"> patch\n\n", "call for a minor release, which belongs on the main branch", "  - feat: b\n"},
+		},
+		{
+			name:       "no recommendation",
+			current:    &version.Version{Major: 1},
+			commits:    []git.Commit{{Message: "docs: a"}},
+			wantCursor: 0,
+		},
+		{
+			name:       "log errors give no recommendation",
+			current:    &version.Version{Major: 1},
+			commits:    testCommits,
+			logErr:     errTest,
+			wantCursor: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Changelog.Commits.ExcludeTypes = tt.exclude
+			app := &App{
+				cfg:     cfg,
+				logger:  slog.Default(),
+				version: &mockVersionService{version: tt.current},
+				git:     &mockGitService{latestTag: "v1.0.0", commits: tt.commits, logErr: tt.logErr},
+				log:     &mockChangelogService{},
+				line:    tt.line,
+			}
+
+			m := initialModel(context.Background(), app)
+			if m.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", m.cursor, tt.wantCursor)
+			}
+			view := m.View()
+			for _, want := range tt.wantView {
+				if !strings.Contains(view, want) {
+					t.Errorf("View() =\n%s\nwant %q", view, want)
+				}
+			}
+			if len(tt.wantView) == 0 && strings.Contains(view, "recommended") {
+				t.Errorf("View() =\n%s\nwant no recommendation", view)
+			}
+		})
+	}
+}
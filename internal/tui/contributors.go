@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/git"
+)
+
+// statsRepo summarises the commits between releases.
+type statsRepo interface {
+	Stats(ctx context.Context, from, to string) (*git.Stats, error)
+}
+
+// contributors lists the authors of the commits since the last release, in
+// alphabetical order, for the changelog.
+func (a *App) contributors(ctx context.Context) ([]changelog.Contributor, error) {
+	if a.stats == nil {
+		return nil, nil
+	}
+	tag, err := a.git.LatestTag(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding the previous release: %w", err)
+	}
+	stats, err := a.stats.Stats(ctx, tag, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	contributors := make([]changelog.Contributor, 0, len(stats.Contributors))
+	for _, c := range stats.Contributors {
+		contributors = append(contributors, changelog.Contributor{Name: c.Name, FirstTime: c.FirstTime})
+	}
+	slices.SortFunc(contributors, func(a, b changelog.Contributor) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return contributors, nil
+}
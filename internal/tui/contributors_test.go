@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+type mockStatsRepo struct {
+	stats *git.Stats
+	err   error
+	// from records the start of the last range.
+	from string
+}
+
+func (m *mockStatsRepo) Stats(ctx context.Context, from, to string) (*git.Stats, error) {
+	m.from = from
+	return m.stats, m.err
+}
+
+func TestContributors(t *testing.T) {
+	stats := &git.Stats{Contributors: []git.Contributor{
+		{Author: git.Author{Name: "zoe", Email: "zoe@example.com"}, Commits: 5},
+		{Author: git.Author{Name: "Bob", Email: "bob@example.com"}, Commits: 2, FirstTime: true},
+		{Author: git.Author{Name: "alice", Email: "alice@example.com"}, Commits: 1},
+	}}
+
+	tests := []struct {
+		name      string
+		stats     statsRepo
+		latestTag string
+		want      []changelog.Contributor
+		wantFrom  string
+		wantErr   error
+	}{
+		{
+			name:      "since the last release",
+			stats:     &mockStatsRepo{stats: stats},
+			latestTag: "v1.0.0",
+			want:      []changelog.Contributor{{Name: "alice"}, {Name: "Bob", FirstTime: true}, {Name: "zoe"}},
+			wantFrom:  "v1.0.0",
+		},
+		{
+			name:  "first release",
+			stats: &mockStatsRepo{stats: &git.Stats{}},
+			want:  []changelog.Contributor{},
+		},
+		{
+			name: "disabled",
+		},
+		{
+			name:    "stats fail",
+			stats:   &mockStatsRepo{err: errTest},
+			wantErr: errTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{git: &mockGitService{latestTag: tt.latestTag}, stats: tt.stats}
+			got, err := app.contributors(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("contributors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contributors() = %+v, want %+v", got, tt.want)
+			}
+			if m, ok := tt.stats.(*mockStatsRepo); ok && m.from != tt.wantFrom {
+				t.Errorf("stats from %q, want %q", m.from, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestSaveChanges_Contributors(t *testing.T) {
+	log := &mockChangelogService{}
+	app := &App{
+		cfg:     &config.Config{},
+		version: &mockVersionService{version: &version.Version{Major: 1}},
+		git:     &mockGitService{latestTag: "v1.0.0"},
+		log:     log,
+		stats: &mockStatsRepo{stats: &git.Stats{Contributors: []git.Contributor{
+			{Author: git.Author{Name: "Carol", Email: "carol@example.com"}, Commits: 1, FirstTime: true},
+		}}},
+	}
+	m := &model{
+		ctx:        context.Background(),
+		app:        app,
+		commitType: version.Patch,
+		shortDesc:  textinput.New(),
+		longDesc:   textarea.New(),
+	}
+
+	if err := m.saveChanges(false); err != nil {
+		t.Fatalf("saveChanges() error = %v", err)
+	}
+	if want := []changelog.Contributor{{Name: "Carol", FirstTime: true}}; !reflect.DeepEqual(log.contributors, want) {
+		t.Errorf("changelog contributors = %+v, want %+v", log.contributors, want)
+	}
+}
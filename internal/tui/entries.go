@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+)
+
+// entryList holds the changelog entries collected for the release.
+type entryList struct {
+	entries        []changelog.Entry
+	entryCursor    int
+	categoryCursor int
+	// editing is the index of the entry being edited, or -1 while a new
+	// entry is being added.
+	editing   int
+	importErr error
+}
+
+func categoryIndex(c changelog.Category) int {
+	for i, known := range changelog.Categories {
+		if known == c {
+			return i
+		}
+	}
+	return 0
+}
+
+// editingCategory is the category preselected for the entry being edited:
+// its current category, or the default for the release type.
+func (m *model) editingCategory() changelog.Category {
+	if m.editing >= 0 && m.editing < len(m.entries) && m.entries[m.editing].Category != "" {
+		return m.entries[m.editing].Category
+	}
+	return changelog.DefaultCategory(m.commitType)
+}
+
+// saveEntry stores the text inputs and selected category as a new entry, or
+// over the entry being edited.
+func (m *model) saveEntry() {
+	keys := m.app.refs.Keys()
+	short, refs := changelog.SplitRefs(m.shortDesc.Value(), keys)
+	long := strings.TrimSpace(m.longDesc.Value())
+	e := changelog.Entry{
+		Category: changelog.Categories[m.categoryCursor],
+		Short:    short,
+		Long:     long,
+		Author:   m.app.author,
+		Refs:     changelog.MergeRefs(refs, changelog.ExtractRefs(long, keys)...),
+	}
+
+	// Models are passed by value, so never modify a shared backing array.
+	m.entries = slices.Clone(m.entries)
+	if m.editing >= 0 && m.editing < len(m.entries) {
+		old := m.entries[m.editing]
+		e.Author = old.Author
+		e.Commit = old.Commit
+		e.Refs = changelog.MergeRefs(slices.Clone(old.Refs), e.Refs...)
+		m.entries[m.editing] = e
+		m.entryCursor = m.editing
+	} else {
+		m.entries = append(m.entries, e)
+		m.entryCursor = len(m.entries) - 1
+	}
+	m.editing = -1
+}
+
+// startEntry switches to the text inputs for entry i, or for a new entry
+// when i is -1.
+func (m *model) startEntry(i int) tea.Cmd {
+	m.editing = i
+	m.shortDesc.Reset()
+	m.longDesc.Reset()
+	if i >= 0 {
+		m.shortDesc.SetValue(m.entries[i].Short)
+		m.longDesc.SetValue(m.entries[i].Long)
+	}
+	m.longDesc.Blur()
+	m.state = stateShortDesc
+	return tea.Batch(m.shortDesc.Focus(), textinput.Blink)
+}
+
+// importCommits appends entries generated from the commits since the last
+// release tag.
+func (m *model) importCommits() {
+	entries, err := m.app.commitEntries(m.ctx)
+	m.importErr = err
+	if err != nil {
+		m.app.logger.Error("failed to read commits", "error", err)
+		return
+	}
+	m.entries = append(slices.Clone(m.entries), entries...)
+}
+
+func (m *model) moveEntry(delta int) {
+	to := m.entryCursor + delta
+	if to < 0 || to >= len(m.entries) {
+		return
+	}
+	m.entries = slices.Clone(m.entries)
+	m.entries[m.entryCursor], m.entries[to] = m.entries[to], m.entries[m.entryCursor]
+	m.entryCursor = to
+}
+
+// updateEntries handles keys for the entry list. It reports false for keys
+// it doesn't use, so that global keys keep working.
+func (m model) updateEntries(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		if m.entryCursor > 0 {
+			m.entryCursor--
+		}
+	case "down", "j":
+		if m.entryCursor < len(m.entries)-1 {
+			m.entryCursor++
+		}
+	case "shift+up", "K":
+		m.moveEntry(-1)
+	case "shift+down", "J":
+		m.moveEntry(1)
+	case "a":
+		cmd := m.startEntry(-1)
+		return m, cmd, true
+	case "e", "enter":
+		if len(m.entries) == 0 {
+			return m, nil, true
+		}
+		cmd := m.startEntry(m.entryCursor)
+		return m, cmd, true
+	case "d", "x", "delete":
+		if len(m.entries) == 0 {
+			return m, nil, true
+		}
+		m.entries = slices.Delete(slices.Clone(m.entries), m.entryCursor, m.entryCursor+1)
+		if m.entryCursor >= len(m.entries) && m.entryCursor > 0 {
+			m.entryCursor--
+		}
+	case "g":
+		m.importCommits()
+	case "c":
+		if len(m.entries) > 0 {
+			cmd := m.continueToConfirm()
+			return m, cmd, true
+		}
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+func (m model) viewEntries() string {
+	s := fmt.Sprintf("Changelog entries for this %s release:\n\n", m.commitType)
+	if len(m.entries) == 0 {
+		s += "  (no entries)\n"
+	}
+	for i, e := range m.entries {
+		cursor := " "
+		if i == m.entryCursor {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s [%s] %s", cursor, e.Category, e.Short)
+		if len(e.Refs) > 0 {
+			s += fmt.Sprintf(" (%s)", strings.Join(e.Refs, ", "))
+		}
+		s += "\n"
+	}
+	if m.importErr != nil {
+		s += fmt.Sprintf("\nCould not read commits: %v\n", m.importErr)
+	}
+	s += "\na: add  e: edit  d: delete  K/J: move up/down  g: add from commits  c: continue  q: quit"
+	return s
+}
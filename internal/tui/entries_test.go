@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "ctrl+x":
+		return tea.KeyMsg{Type: tea.KeyCtrlX}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func send(m model, keys ...string) model {
+	for _, k := range keys {
+		next, _ := m.Update(key(k))
+		m = next.(model)
+	}
+	return m
+}
+
+func newEntriesModel() model {
+	app := &App{
+		cfg:     &config.Config{},
+		logger:  slog.Default(),
+		version: &mockVersionService{version: &version.Version{Major: 1, Minor: 0, Patch: 0}},
+		git:     &mockGitService{},
+		log:     &mockChangelogService{},
+	}
+	return initialModel(context.Background(), app)
+}
+
+func TestEntryList(t *testing.T) {
+	// Patch release, first entry "one" in the default Fixed category.
+	m := send(newEntriesModel(), "down", "down", "enter", "o", "n", "e", "enter", "ctrl+s", "enter")
+	if m.state != stateEntries {
+		t.Fatalf("state = %v, want %v", m.state, stateEntries)
+	}
+	if len(m.entries) != 1 || m.entries[0].Short != "one" || m.entries[0].Category != changelog.Fixed {
+		t.Fatalf("entries = %+v", m.entries)
+	}
+
+	// Add a second entry with a long description over two lines, moving
+	// the category cursor from Fixed to Security.
+	m = send(m, "a", "t", "w", "o", "enter", "q", "enter", "r", "ctrl+s", "down", "enter")
+	if len(m.entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(m.entries))
+	}
+	want := changelog.Entry{Category: changelog.Security, Short: "two", Long: "q\nr"}
+	if !reflect.DeepEqual(m.entries[1], want) {
+		t.Errorf("entries[1] = %+v, want %+v", m.entries[1], want)
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{"move up", []string{"K"}, []string{"two", "one"}},
+		{"move down at the end is a no-op", []string{"J"}, []string{"one", "two"}},
+		{"delete", []string{"d"}, []string{"one"}},
+		{"edit", []string{"k", "e", "!", "enter", "ctrl+s", "enter"}, []string{"one!", "two"}},
+		{"cancel add", []string{"a", "x", "esc"}, []string{"one", "two"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := send(m, tt.keys...)
+			if got.state != stateEntries {
+				t.Fatalf("state = %v, want %v", got.state, stateEntries)
+			}
+			var shorts []string
+			for _, e := range got.entries {
+				shorts = append(shorts, e.Short)
+			}
+			if strings.Join(shorts, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("entries = %v, want %v", shorts, tt.want)
+			}
+		})
+	}
+
+	if got := send(m, "c"); got.state != stateConfirm {
+		t.Errorf("state after continue = %v, want %v", got.state, stateConfirm)
+	}
+	if got := send(m, "d", "d", "c"); got.state != stateEntries {
+		t.Errorf("continue without entries moved to %v", got.state)
+	}
+}
+
+func TestEntryList_Refs(t *testing.T) {
+	m := newEntriesModel()
+	m.app.author = "alice"
+
+	// Patch release with a reference in the short description and another
+	// in the long one.
+	m = send(m, "down", "down", "enter")
+	for _, r := range "Fix race (#12)" {
+		m = send(m, string(r))
+	}
+	m = send(m, "enter", "#", "3", "ctrl+s", "enter")
+
+	want := changelog.Entry{Category: changelog.Fixed, Short: "Fix race", Long: "#3", Author: "alice", Refs: []string{"#12", "#3"}}
+	if len(m.entries) != 1 || !reflect.DeepEqual(m.entries[0], want) {
+		t.Fatalf("entries = %+v, want %+v", m.entries, want)
+	}
+	if view := m.viewEntries(); !strings.Contains(view, "Fix race (#12, #3)") {
+		t.Errorf("view missing references:\n%s", view)
+	}
+
+	// Editing keeps references that are no longer in the text.
+	m = send(m, "e", "!", "enter", "ctrl+s", "enter")
+	if !reflect.DeepEqual(m.entries[0].Refs, []string{"#12", "#3"}) {
+		t.Errorf("Refs after edit = %q", m.entries[0].Refs)
+	}
+}
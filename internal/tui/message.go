@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var ErrInvalidCommitTemplate = errors.New("invalid release commit template")
+
+// defaultCommitMessage gives release commits a Conventional Commit header,
+// so tools can find them, and lists the entries in the body.
+const defaultCommitMessage = `chore(release): {{.Tag}}{{with .Entries}}
+
+{{range .}}- {{.Category}}: {{.Short}}
+{{end}}{{end}}`
+
+// commitData is passed to the release commit templates.
+type commitData struct {
+	Version string
+	Tag     string
+	Type    version.Type
+	Entries []changelog.Entry
+}
+
+type trailer struct {
+	token string
+	value *template.Template
+}
+
+type commitTemplate struct {
+	message  *template.Template
+	trailers []trailer
+}
+
+var defaultCommitTemplate = &commitTemplate{
+	message: template.Must(template.New("message").Parse(defaultCommitMessage)),
+}
+
+func newCommitTemplate(cfg config.ReleaseCommitConfig) (*commitTemplate, error) {
+	text := cfg.Message
+	if text == "" {
+		text = defaultCommitMessage
+	}
+	message, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCommitTemplate, err)
+	}
+
+	t := &commitTemplate{message: message}
+	for _, tr := range cfg.Trailers {
+		if !git.TrailerToken.MatchString(tr.Token) {
+			return nil, fmt.Errorf("%w: trailer token %q", ErrInvalidCommitTemplate, tr.Token)
+		}
+		value, err := template.New(tr.Token).Parse(tr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: trailer %s: %v", ErrInvalidCommitTemplate, tr.Token, err)
+		}
+		t.trailers = append(t.trailers, trailer{token: tr.Token, value: value})
+	}
+
+	sample := commitData{
+		Version: "1.2.0",
+		Tag:     "v1.2.0",
+		Type:    version.Minor,
+		Entries: []changelog.Entry{{Category: changelog.Added, Short: "Short description"}},
+	}
+	msg, err := t.render(sample)
+	if err != nil {
+		return nil, err
+	}
+	if msg == "" {
+		return nil, fmt.Errorf("%w: the message is empty", ErrInvalidCommitTemplate)
+	}
+	return t, nil
+}
+
+// render returns the commit message followed by its trailers.
+func (t *commitTemplate) render(data commitData) (string, error) {
+	var b strings.Builder
+	if err := t.message.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCommitTemplate, err)
+	}
+	msg := strings.TrimSpace(b.String())
+
+	var trailers []string
+	for _, tr := range t.trailers {
+		var v strings.Builder
+		if err := tr.value.Execute(&v, data); err != nil {
+			return "", fmt.Errorf("%w: trailer %s: %v", ErrInvalidCommitTemplate, tr.token, err)
+		}
+		if value := strings.Join(strings.Fields(v.String()), " "); value != "" {
+			trailers = append(trailers, tr.token+": "+value)
+		}
+	}
+	if len(trailers) > 0 {
+		msg += "\n\n" + strings.Join(trailers, "\n")
+	}
+	return msg, nil
+}
+
+// commitMessage renders the release commit message for ver.
+func (a *App) commitMessage(ver *version.Version, t version.Type, entries []changelog.Entry) (string, error) {
+	tmpl := a.commit
+	if tmpl == nil {
+		tmpl = defaultCommitTemplate
+	}
+	return tmpl.render(commitData{
+		Version: ver.String(),
+		Tag:     a.tags.Name(ver),
+		Type:    t,
+		Entries: entries,
+	})
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestCommitMessage(t *testing.T) {
+	ver := &version.Version{Major: 1, Minor: 2, Patch: 0}
+	entries := []changelog.Entry{
+		{Category: changelog.Added, Short: "feature", Long: "details"},
+		{Category: changelog.Fixed, Short: "bug"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.ReleaseCommitConfig
+		// tagFormat names the release tag; empty is the default.
+		tagFormat string
+		entries   []changelog.Entry
+		want      string
+		wantErr   error
+	}{
+		{
+			name: "no entries",
+			want: "chore(release): v1.2.0",
+		},
+		{
+			name:    "entries",
+			entries: entries,
+			want:    "chore(release): v1.2.0\n\n- Added: feature\n- Fixed: bug",
+		},
+		{
+			name:      "component tags",
+			tagFormat: "api/v{{.Version}}",
+			want:      "chore(release): api/v1.2.0",
+		},
+		{
+			name: "trailers",
+			cfg: config.ReleaseCommitConfig{
+				Trailers: []config.TrailerConfig{
+					{Token: "Release-Version", Value: "{{.Version}}"},
+					{Token: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+				},
+			},
+			entries: entries[1:],
+			want:    "chore(release): v1.2.0\n\n- Fixed: bug\n\nRelease-Version: 1.2.0\nCo-authored-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name: "custom message and empty trailer",
+			cfg: config.ReleaseCommitConfig{
+				Message: "release: {{.Tag}} ({{.Type}})\n",
+				Trailers: []config.TrailerConfig{
+					{Token: "Breaking", Value: `{{if eq .Type "major"}}yes{{end}}`},
+					{Token: "Release-Version", Value: "{{.Version}}"},
+				},
+			},
+			want: "release: v1.2.0 (minor)\n\nRelease-Version: 1.2.0",
+		},
+		{
+			name:    "invalid template",
+			cfg:     config.ReleaseCommitConfig{Message: "release {{.Version"},
+			wantErr: ErrInvalidCommitTemplate,
+		},
+		{
+			name:    "unknown field",
+			cfg:     config.ReleaseCommitConfig{Message: "release {{.Name}}"},
+			wantErr: ErrInvalidCommitTemplate,
+		},
+		{
+			name:    "empty message",
+			cfg:     config.ReleaseCommitConfig{Message: "{{/* nothing */}}"},
+			wantErr: ErrInvalidCommitTemplate,
+		},
+		{
+			name: "invalid trailer token",
+			cfg: config.ReleaseCommitConfig{
+				Trailers: []config.TrailerConfig{{Token: "Release Version", Value: "x"}},
+			},
+			wantErr: ErrInvalidCommitTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := newCommitTemplate(tt.cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("newCommitTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			tags, err := git.NewTagFormat(tt.tagFormat, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			app := &App{commit: tmpl, tags: tags}
+			got, err := app.commitMessage(ver, version.Minor, tt.entries)
+			if err != nil {
+				t.Fatalf("commitMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("commitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// migrationNotes holds the upgrade instructions required for a major
+// release.
+type migrationNotes struct {
+	migration textarea.Model
+	// skipMigration records that the user explicitly chose to release a
+	// major version without migration notes.
+	skipMigration bool
+	// migrationMissing is set after an attempt to save empty notes.
+	migrationMissing bool
+}
+
+func newMigrationNotes() migrationNotes {
+	ta := textarea.New()
+	ta.Placeholder = "Describe how users should migrate to this release"
+	ta.ShowLineNumbers = false
+	ta.SetWidth(72)
+	return migrationNotes{migration: ta}
+}
+
+// continueToConfirm moves on from the entry list, asking for migration
+// notes first on a major release.
+func (m *model) continueToConfirm() tea.Cmd {
+	if m.commitType != version.Major {
+		m.state = stateConfirm
+		return nil
+	}
+	m.migrationMissing = false
+	m.state = stateMigration
+	return m.migration.Focus()
+}
+
+func (m model) migrationText() string {
+	if m.commitType != version.Major || m.skipMigration {
+		return ""
+	}
+	return strings.TrimSpace(m.migration.Value())
+}
+
+// updateMigration handles the keys that finish the migration notes. It
+// reports false for everything else, which goes to the text area.
+func (m model) updateMigration(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+s":
+		if strings.TrimSpace(m.migration.Value()) == "" {
+			m.migrationMissing = true
+			return m, nil, true
+		}
+		m.skipMigration = false
+	case "ctrl+x":
+		m.skipMigration = true
+	default:
+		return m, nil, false
+	}
+	m.migration.Blur()
+	m.state = stateConfirm
+	return m, nil, true
+}
+
+func (m model) viewMigration() string {
+	s := "Migration notes (required for a major release):\n\n"
+	s += m.migration.View()
+	if m.migrationMissing {
+		s += "\n\nMigration notes can't be empty. Press ctrl+x to release without them."
+	}
+	s += "\n\nctrl+s: save  ctrl+x: skip  esc: back to entries"
+	return s
+}
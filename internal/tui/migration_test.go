@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestMigrationNotes(t *testing.T) {
+	// Major release with a single entry, then continue from the entry list.
+	major := func() model {
+		m := send(newEntriesModel(), "enter", "o", "n", "e", "enter", "ctrl+s", "enter", "c")
+		if m.state != stateMigration {
+			t.Fatalf("state = %v, want %v", m.state, stateMigration)
+		}
+		return m
+	}
+
+	t.Run("notes are saved with the release", func(t *testing.T) {
+		m := send(major(), "R", "u", "n", "enter", "x", "ctrl+s")
+		if m.state != stateConfirm {
+			t.Fatalf("state = %v, want %v", m.state, stateConfirm)
+		}
+		m = send(m, "y")
+		if got := m.app.log.(*mockChangelogService).migration; got != "Run\nx" {
+			t.Errorf("migration = %q, want %q", got, "Run\nx")
+		}
+	})
+
+	t.Run("empty notes need an explicit override", func(t *testing.T) {
+		m := send(major(), "ctrl+s")
+		if m.state != stateMigration || !m.migrationMissing {
+			t.Fatalf("state = %v, migrationMissing = %v; want to stay on the prompt", m.state, m.migrationMissing)
+		}
+		m = send(m, "ctrl+x")
+		if m.state != stateConfirm || !m.skipMigration {
+			t.Fatalf("state = %v, skipMigration = %v", m.state, m.skipMigration)
+		}
+		m = send(m, "y")
+		if got := m.app.log.(*mockChangelogService).migration; got != "" {
+			t.Errorf("migration = %q, want none", got)
+		}
+	})
+
+	t.Run("q is typed, esc goes back", func(t *testing.T) {
+		m := send(major(), "q")
+		if m.quitting || m.migration.Value() != "q" {
+			t.Fatalf("quitting = %v, value = %q", m.quitting, m.migration.Value())
+		}
+		m = send(m, "esc")
+		if m.state != stateEntries {
+			t.Errorf("state = %v, want %v", m.state, stateEntries)
+		}
+	})
+
+	t.Run("minor releases skip the prompt", func(t *testing.T) {
+		m := send(newEntriesModel(), "down", "enter", "o", "enter", "ctrl+s", "enter", "c")
+		if m.state != stateConfirm {
+			t.Errorf("state = %v, want %v", m.state, stateConfirm)
+		}
+	})
+}
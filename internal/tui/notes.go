@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/notes"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+// notesRepo is where release metadata is recorded.
+type notesRepo interface {
+	notes.Repo
+	Identity(ctx context.Context) (string, error)
+	PushRef(ctx context.Context, remote, ref string) error
+}
+
+// recordRelease attaches the metadata of release ver, which follows
+// previous, to the release commit at HEAD. previous is the version the
+// version file held, as the latest tag may be on another maintenance line or
+// missing.
+func (a *App) recordRelease(ctx context.Context, previous, ver *version.Version, t version.Type, entries []changelog.Entry) error {
+	if a.notes == nil {
+		return nil
+	}
+
+	var prev string
+	if previous != nil {
+		prev = previous.String()
+	}
+	by, err := a.notes.Identity(ctx)
+	if err != nil {
+		return err
+	}
+
+	return notes.Add(ctx, a.notes, "HEAD", &notes.Release{
+		Schema:          notes.SchemaVersion,
+		Version:         ver.String(),
+		PreviousVersion: prev,
+		Type:            t,
+		Entries:         notes.Entries(entries),
+		ToolVersion:     notes.ToolVersion(),
+		ReleasedBy:      by,
+		ReleasedAt:      a.clock.Now(),
+	})
+}
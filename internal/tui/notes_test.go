@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/clock"
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/notes"
+	"github.com/WagnerMatos/semver/internal/version"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+type mockNotesRepo struct {
+	// added records the notes by revision.
+	added       map[string]string
+	addErr      error
+	identityErr error
+	// pushed records the remote and ref of each push.
+	pushed  []string
+	pushErr error
+}
+
+func (m *mockNotesRepo) AddNote(ctx context.Context, ref, rev, note string) error {
+	if m.added == nil {
+		m.added = make(map[string]string)
+	}
+	m.added[rev] = note
+	return m.addErr
+}
+
+func (m *mockNotesRepo) Notes(ctx context.Context, ref string) (map[string]string, error) {
+	return m.added, nil
+}
+
+func (m *mockNotesRepo) PushRef(ctx context.Context, remote, ref string) error {
+	m.pushed = append(m.pushed, remote, ref)
+	return m.pushErr
+}
+
+func (m *mockNotesRepo) Identity(ctx context.Context) (string, error) {
+	return "Test User <test@example.com>", m.identityErr
+}
+
+func TestRecordRelease(t *testing.T) {
+	releasedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []changelog.Entry{{Category: changelog.Added, Short: "Export", Commit: "abc1234"}}
+
+	tests := []struct {
+		name         string
+		previous     *version.Version
+		identityErr  error
+		addErr       error
+		wantPrevious string
+		wantErr      error
+	}{
+		{name: "previous release", previous: &version.Version{Major: 1}, wantPrevious: "1.0.0"},
+		{name: "first release"},
+		{name: "no identity", previous: &version.Version{Major: 1}, identityErr: errTest, wantErr: errTest},
+		{name: "note fails", previous: &version.Version{Major: 1}, addErr: errTest, wantErr: errTest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockNotesRepo{identityErr: tt.identityErr, addErr: tt.addErr}
+			app := &App{
+				notes: repo,
+				clock: clock.Fixed(releasedAt),
+			}
+
+			ver := &version.Version{Major: 1, Minor: 1}
+			err := app.recordRelease(context.Background(), tt.previous, ver, version.Minor, entries)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("recordRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := notes.Parse(repo.added["HEAD"])
+			if err != nil {
+				t.Fatalf("note on HEAD: %v", err)
+			}
+			want := &notes.Release{
+				Schema:          notes.SchemaVersion,
+				Version:         "1.1.0",
+				PreviousVersion: tt.wantPrevious,
+				Type:            version.Minor,
+				Entries:         notes.Entries(entries),
+				ToolVersion:     notes.ToolVersion(),
+				ReleasedBy:      "Test User <test@example.com>",
+				ReleasedAt:      releasedAt,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("note = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSaveChanges_RecordsRelease(t *testing.T) {
+	repo := &mockNotesRepo{}
+	app := &App{
+		cfg:     &config.Config{},
+		version: &mockVersionService{version: &version.Version{Major: 1}},
+		// 1.0.0 was never tagged, so the latest tag is an older release.
+		git:   &mockGitService{latestTag: "v0.9.0"},
+		log:   &mockChangelogService{},
+		notes: repo,
+		clock: clock.Wall,
+	}
+	m := &model{
+		ctx:        context.Background(),
+		app:        app,
+		commitType: version.Patch,
+		shortDesc:  textinput.New(),
+		longDesc:   textarea.New(),
+	}
+
+	if err := m.saveChanges(true); err != nil {
+		t.Fatalf("saveChanges() error = %v", err)
+	}
+	r, err := notes.Parse(repo.added["HEAD"])
+	if err != nil {
+		t.Fatalf("note on HEAD: %v", err)
+	}
+	if r.Version != "1.0.1" || r.PreviousVersion != "1.0.0" || r.Type != version.Patch {
+		t.Errorf("note = %+v, want 1.0.1 after 1.0.0", r)
+	}
+}
+
+func TestSaveChanges_RecordFailure(t *testing.T) {
+	var logs bytes.Buffer
+	gitSvc := &mockGitService{latestTag: "v1.0.0"}
+	app := &App{
+		cfg:     &config.Config{},
+		version: &mockVersionService{version: &version.Version{Major: 1}},
+		git:     gitSvc,
+		log:     &mockChangelogService{},
+		notes:   &mockNotesRepo{addErr: errTest},
+		clock:   clock.Wall,
+		logger:  slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+	m := &model{
+		ctx:        context.Background(),
+		app:        app,
+		commitType: version.Patch,
+		shortDesc:  textinput.New(),
+		longDesc:   textarea.New(),
+	}
+
+	if err := m.saveChanges(true); err != nil {
+		t.Fatalf("saveChanges() error = %v", err)
+	}
+	if !gitSvc.tagged {
+		t.Error("saveChanges() didn't tag the release")
+	}
+	if !strings.Contains(logs.String(), "release metadata not recorded") {
+		t.Errorf("logs = %q, want a warning", logs.String())
+	}
+}
+
+func TestPush_SendsReleaseMetadata(t *testing.T) {
+	tests := []struct {
+		name       string
+		recorded   bool
+		pushErr    error
+		wantPushed []string
+	}{
+		{name: "recorded", recorded: true, wantPushed: []string{"origin", notes.Ref}},
+		{name: "not recorded"},
+		{name: "metadata push fails", recorded: true, pushErr: errTest, wantPushed: []string{"origin", notes.Ref}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockNotesRepo{pushErr: tt.pushErr}
+			gitSvc := &mockGitService{}
+			m := &model{
+				ctx: context.Background(),
+				app: &App{
+					cfg:    &config.Config{Push: config.PushConfig{Remote: "origin"}},
+					git:    gitSvc,
+					notes:  repo,
+					logger: slog.Default(),
+				},
+				recorded: tt.recorded,
+			}
+
+			if err := m.push(); err != nil {
+				t.Fatalf("push() error = %v", err)
+			}
+			if len(gitSvc.pushed) == 0 {
+				t.Error("push() didn't push the release")
+			}
+			if !reflect.DeepEqual(repo.pushed, tt.wantPushed) {
+				t.Errorf("pushed metadata %q, want %q", repo.pushed, tt.wantPushed)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+var (
+	ErrNoChanges         = errors.New("no changes since the last release")
+	ErrMigrationRequired = errors.New("a major release needs migration notes")
+)
+
+// ReleaseOptions configure a release made without the interactive UI.
+type ReleaseOptions struct {
+	Type version.Type
+	Tag  bool
+	Push bool
+	// Migration holds the migration notes of a major release. A major
+	// release without them must set NoMigration, as in the TUI.
+	Migration   string
+	NoMigration bool
+}
+
+// Release records the commits since the last release tag as a new release,
+// exactly as confirming them in the TUI would, then tags and pushes it as
+// requested.
+func (a *App) Release(ctx context.Context, opts ReleaseOptions) (*version.Version, error) {
+	if opts.Type == version.Major && strings.TrimSpace(opts.Migration) == "" && !opts.NoMigration {
+		return nil, ErrMigrationRequired
+	}
+
+	entries, err := a.commitEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	m := &model{
+		ctx:            ctx,
+		app:            a,
+		commitType:     opts.Type,
+		entryList:      entryList{entries: entries, editing: -1},
+		migrationNotes: newMigrationNotes(),
+	}
+	m.migration.SetValue(opts.Migration)
+	m.skipMigration = opts.NoMigration
+	if err := m.saveChanges(opts.Tag); err != nil {
+		return nil, err
+	}
+
+	if opts.Push {
+		if err := m.push(); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.version.Read()
+}
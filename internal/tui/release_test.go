@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/version"
+)
+
+func TestApp_Release(t *testing.T) {
+	commits := []git.Commit{{Hash: "abc", Author: "Alice", Message: "feat: add export"}}
+
+	tests := []struct {
+		name       string
+		commits    []git.Commit
+		opts       ReleaseOptions
+		wantPushed []string
+		// wantVersion defaults to 1.1.0.
+		wantVersion   *version.Version
+		wantMigration string
+		wantErr       error
+	}{
+		{
+			name:    "tag only",
+			commits: commits,
+			opts:    ReleaseOptions{Type: version.Minor, Tag: true},
+		},
+		{
+			name:       "tag and push",
+			commits:    commits,
+			opts:       ReleaseOptions{Type: version.Minor, Tag: true, Push: true},
+			wantPushed: []string{"origin", "", "v1.1.0"},
+		},
+		{
+			name:       "push without a tag",
+			commits:    commits,
+			opts:       ReleaseOptions{Type: version.Minor, Push: true},
+			wantPushed: []string{"origin", "", ""},
+		},
+		{
+			name:          "major with migration notes",
+			commits:       commits,
+			opts:          ReleaseOptions{Type: version.Major, Migration: "Rename the config file.\n"},
+			wantVersion:   &version.Version{Major: 2},
+			wantMigration: "Rename the config file.",
+		},
+		{
+			name:        "major without migration notes by choice",
+			commits:     commits,
+			opts:        ReleaseOptions{Type: version.Major, NoMigration: true, Migration: "ignored"},
+			wantVersion: &version.Version{Major: 2},
+		},
+		{
+			name:    "major without migration notes",
+			commits: commits,
+			opts:    ReleaseOptions{Type: version.Major, Migration: "  "},
+			wantErr: ErrMigrationRequired,
+		},
+		{
+			name:    "nothing to release",
+			opts:    ReleaseOptions{Type: version.Patch, Tag: true},
+			wantErr: ErrNoChanges,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitSvc := &mockGitService{commits: tt.commits}
+			log := &mockChangelogService{}
+			app := &App{
+				cfg:     &config.Config{Push: config.PushConfig{Remote: "origin"}},
+				logger:  slog.Default(),
+				version: &mockVersionService{version: &version.Version{Major: 1}},
+				git:     gitSvc,
+				log:     log,
+			}
+
+			ver, err := app.Release(context.Background(), tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Release() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			want := tt.wantVersion
+			if want == nil {
+				want = &version.Version{Major: 1, Minor: 1}
+			}
+			if ver.Compare(want) != 0 {
+				t.Errorf("Release() = %v, want %v", ver, want)
+			}
+			if log.migration != tt.wantMigration {
+				t.Errorf("migration notes = %q, want %q", log.migration, tt.wantMigration)
+			}
+			if !slices.Equal(gitSvc.pushed, tt.wantPushed) {
+				t.Errorf("pushed %q, want %q", gitSvc.pushed, tt.wantPushed)
+			}
+		})
+	}
+}
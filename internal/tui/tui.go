@@ -5,14 +5,20 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/WagnerMatos/semver/internal/changelog"
+	"github.com/WagnerMatos/semver/internal/clock"
 	"github.com/WagnerMatos/semver/internal/config"
 	"github.com/WagnerMatos/semver/internal/git"
+	"github.com/WagnerMatos/semver/internal/notes"
+	"github.com/WagnerMatos/semver/internal/preflight"
 	"github.com/WagnerMatos/semver/internal/version"
 )
 
@@ -22,28 +28,173 @@ type App struct {
 	version version.Service
 	git     git.Service
 	log     changelog.Service
+	// repo is checked before a release; nil skips the checks.
+	repo preflight.Repo
+	refs *changelog.RefURLs
+	// commit renders release commit messages; nil uses the default.
+	commit *commitTemplate
+	// tags names release tags; nil is the default format.
+	tags *git.TagFormat
+	// author is credited on entries written in the TUI.
+	author string
+	// line is the maintenance line of the release branch checked out, nil
+	// on any other branch.
+	line *version.Line
+	// notes records release metadata on release commits; nil skips it.
+	notes notesRepo
+	// clock dates the release metadata.
+	clock clock.Clock
+	// stats credits contributors in the changelog; nil leaves them out.
+	stats   statsRepo
 	testing bool
 }
 
-func New(cfg *config.Config, logger *slog.Logger) *App {
-	return &App{
+func New(cfg *config.Config, logger *slog.Logger) (*App, error) {
+	backend, err := git.NewBackend(cfg.GitBackend)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := git.NewTagFormat(cfg.Tag.Format, cfg.Tag.Package)
+	if err != nil {
+		return nil, err
+	}
+	yanked, err := changelog.YankedFile(cfg.ChangelogFile)
+	if err != nil {
+		return nil, err
+	}
+	gitOpts := []git.Option{
+		git.WithBackend(backend),
+		git.WithTagFormat(tags),
+		git.WithYanked(yanked...),
+		git.WithIncludeStaged(cfg.IncludeStaged),
+		git.WithSignOff(cfg.ReleaseCommit.SignOff),
+	}
+	if cfg.Tag.Sign {
+		gitOpts = append(gitOpts, git.WithSigning(git.Signing{
+			Key:    cfg.Tag.SigningKey,
+			Format: cfg.Tag.SigningFormat,
+		}))
+	}
+	gitSvc := git.New(gitOpts...)
+
+	templates, err := changelog.NewTemplates(cfg.Changelog.Preset, changelog.TemplateSource{
+		Heading:   cfg.Changelog.HeadingTemplate,
+		Section:   cfg.Changelog.SectionTemplate,
+		Entry:     cfg.Changelog.EntryTemplate,
+		WrapWidth: cfg.Changelog.WrapWidth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading changelog templates: %w", err)
+	}
+
+	opts := []changelog.Option{changelog.WithTemplates(templates)}
+	links := newLinks(cfg, gitSvc, tags, logger)
+	if links != nil {
+		opts = append(opts, changelog.WithLinks(links))
+	}
+
+	refs, err := changelog.NewRefURLs(links, cfg.Changelog.RefURLs)
+	if err != nil {
+		return nil, fmt.Errorf("loading reference urls: %w", err)
+	}
+	opts = append(opts, changelog.WithRefURLs(refs))
+
+	commit, err := newCommitTemplate(cfg.ReleaseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	clk, err := clock.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, changelog.WithClock(clk), changelog.WithDateLayout(cfg.Changelog.DateLayout))
+
+	author, err := gitSvc.Author(context.Background())
+	if err != nil {
+		logger.Debug("no author configured, entries won't be credited", "error", err)
+	}
+	if cfg.UpgradingFile != "" {
+		opts = append(opts, changelog.WithUpgrading(cfg.UpgradingFile))
+	}
+
+	var versionOpts []version.Option
+	line, latest, err := gitSvc.MaintenanceLine(context.Background())
+	if err != nil {
+		logger.Debug("cannot tell the branch, assuming it isn't a release branch", "error", err)
+	}
+	if line != nil {
+		versionOpts = append(versionOpts, version.WithLine(*line, latest))
+	}
+
+	app := &App{
 		cfg:     cfg,
 		logger:  logger,
-		version: version.NewFileService(cfg.VersionFile),
-		git:     git.New(),
-		log:     changelog.New(cfg.ChangelogFile),
+		version: version.NewFileService(cfg.VersionFile, versionOpts...),
+		git:     gitSvc,
+		log:     changelog.New(cfg.ChangelogFile, opts...),
+		repo:    gitSvc,
+		commit:  commit,
+		tags:    tags,
+		refs:    refs,
+		author:  author,
+		line:    line,
+		notes:   gitSvc,
+		clock:   clk,
+	}
+	if cfg.Changelog.Contributors {
+		app.stats = gitSvc
+	}
+	return app, nil
+}
+
+// newLinks configures compare links from the config, falling back to the
+// origin remote. Links are optional, so problems are logged and nil is
+// returned.
+func newLinks(cfg *config.Config, gitSvc git.Service, tags *git.TagFormat, logger *slog.Logger) *changelog.Links {
+	repo := cfg.Changelog.RepositoryURL
+	if repo == "" {
+		remote, err := gitSvc.RemoteURL(context.Background(), "origin")
+		if err != nil {
+			logger.Debug("no origin remote, skipping changelog links", "error", err)
+			return nil
+		}
+		if repo, err = changelog.RepositoryURL(remote); err != nil {
+			logger.Warn("skipping changelog links", "error", err)
+			return nil
+		}
+	}
+
+	links, err := changelog.NewLinks(repo, changelog.Host(cfg.Changelog.Host), changelog.LinkTemplates{
+		Compare: cfg.Changelog.CompareURL,
+		Tag:     cfg.Changelog.TagURL,
+	})
+	if err != nil {
+		logger.Warn("skipping changelog links", "error", err)
+		return nil
+	}
+	links.TagName = func(v string) string {
+		ver, err := version.ParseVersion(v)
+		if err != nil {
+			return v
+		}
+		return tags.Name(ver)
 	}
+	return links
 }
 
-func NewTest(cfg *config.Config, logger *slog.Logger) *App {
-	app := New(cfg, logger)
+func NewTest(cfg *config.Config, logger *slog.Logger) (*App, error) {
+	app, err := New(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
 	app.testing = true
-	return app
+	return app, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
 	if a.testing {
-		ver := &version.Version{0, 1, 0}
+		ver := &version.Version{Major: 0, Minor: 1, Patch: 0}
 		if err := a.version.Write(ver); err != nil {
 			return fmt.Errorf("writing initial version: %w", err)
 		}
@@ -73,10 +224,23 @@ type model struct {
 	state      state
 	cursor     int
 	commitType version.Type
-	shortDesc  textinput.Model
-	longDesc   textinput.Model
-	err        error
-	quitting   bool
+	// types are the bumps offered: only patch on a maintenance line.
+	types []version.Type
+	// recommended is the bump the commits since the last release call for,
+	// and drivers are the commits that call for it.
+	recommended version.Type
+	drivers     []string
+	shortDesc   textinput.Model
+	longDesc    textarea.Model
+	entryList
+	migrationNotes
+	// tagged and recorded are set once the release is tagged and its
+	// metadata is on the release commit, so that pushing the release sends
+	// them too.
+	tagged   bool
+	recorded bool
+	err      error
+	quitting bool
 }
 
 type state int
@@ -85,10 +249,17 @@ const (
 	stateCommitType state = iota
 	stateShortDesc
 	stateLongDesc
+	stateCategory
+	stateEntries
+	stateMigration
 	stateConfirm
 	stateTagConfirm
+	statePushConfirm
 )
 
+// maxDrivers caps the commits listed to explain a bump recommendation.
+const maxDrivers = 5
+
 var (
 	commitTypes = []version.Type{version.Major, version.Minor, version.Patch}
 	style       = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
@@ -99,46 +270,101 @@ func initialModel(ctx context.Context, app *App) model {
 	shortDesc.Placeholder = "Enter short description"
 	shortDesc.Focus()
 
-	longDesc := textinput.New()
+	longDesc := textarea.New()
 	longDesc.Placeholder = "Enter long description (optional)"
+	longDesc.ShowLineNumbers = false
+	longDesc.SetWidth(72)
+
+	m := model{
+		ctx:            ctx,
+		app:            app,
+		state:          stateCommitType,
+		shortDesc:      shortDesc,
+		longDesc:       longDesc,
+		entryList:      entryList{editing: -1},
+		migrationNotes: newMigrationNotes(),
+		types:          commitTypes,
+	}
+	if app.line != nil {
+		m.types = []version.Type{version.Patch}
+	}
 
-	return model{
-		ctx:       ctx,
-		app:       app,
-		state:     stateCommitType,
-		shortDesc: shortDesc,
-		longDesc:  longDesc,
+	m.recommended, m.drivers = app.recommendBump(ctx)
+	if i := slices.Index(m.types, m.recommended); i >= 0 {
+		m.cursor = i
 	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+// typing reports whether key presses are going to a text input.
+func (m model) typing() bool {
+	return m.state == stateShortDesc || m.state == stateLongDesc || m.state == stateMigration
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.state == stateEntries {
+			if next, cmd, handled := m.updateEntries(msg); handled {
+				return next, cmd
+			}
+		}
+		if m.state == stateMigration {
+			if next, cmd, handled := m.updateMigration(msg); handled {
+				return next, cmd
+			}
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
 
+		case "q":
+			if !m.typing() {
+				m.quitting = true
+				return m, tea.Quit
+			}
+
+		case "esc":
+			if (m.typing() || m.state == stateCategory) && len(m.entries) > 0 {
+				m.migration.Blur()
+				m.state = stateEntries
+				return m, nil
+			}
+
 		case "up", "k":
-			if m.state == stateCommitType {
+			switch m.state {
+			case stateCommitType:
 				m.cursor--
 				if m.cursor < 0 {
-					m.cursor = len(commitTypes) - 1
+					m.cursor = len(m.types) - 1
+				}
+			case stateCategory:
+				m.categoryCursor--
+				if m.categoryCursor < 0 {
+					m.categoryCursor = len(changelog.Categories) - 1
 				}
 			}
 
 		case "down", "j":
-			if m.state == stateCommitType {
+			switch m.state {
+			case stateCommitType:
 				m.cursor++
-				if m.cursor >= len(commitTypes) {
+				if m.cursor >= len(m.types) {
 					m.cursor = 0
 				}
+			case stateCategory:
+				m.categoryCursor++
+				if m.categoryCursor >= len(changelog.Categories) {
+					m.categoryCursor = 0
+				}
 			}
 
 		case "y", "Y":
@@ -155,28 +381,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if err := m.createTag(); err != nil {
 					m.err = err
 					m.app.logger.Error("failed to create tag", "error", err)
+					m.quitting = true
+					return m, tea.Quit
+				}
+				m.state = statePushConfirm
+			case statePushConfirm:
+				if err := m.push(); err != nil {
+					m.err = err
+					m.app.logger.Error("failed to push release", "error", err)
 				}
 				m.quitting = true
 				return m, tea.Quit
 			}
 
 		case "n", "N":
-			if m.state == stateConfirm || m.state == stateTagConfirm {
+			switch m.state {
+			case stateTagConfirm:
+				// The release commit can still be pushed without a tag.
+				m.state = statePushConfirm
+			case stateConfirm, statePushConfirm:
 				m.quitting = true
 				return m, tea.Quit
 			}
 
+		case "ctrl+s":
+			// Enter starts a new line of the long description, so it is
+			// finished with ctrl+s like the migration notes.
+			if m.state == stateLongDesc {
+				m.longDesc.Blur()
+				m.categoryCursor = categoryIndex(m.editingCategory())
+				m.state = stateCategory
+				return m, nil
+			}
+
 		case "enter":
 			switch m.state {
 			case stateCommitType:
-				m.commitType = commitTypes[m.cursor]
+				m.commitType = m.types[m.cursor]
 				m.state = stateShortDesc
+				if m.app.cfg.Changelog.FromCommits {
+					m.importCommits()
+					if len(m.entries) > 0 {
+						m.state = stateEntries
+						return m, nil
+					}
+				}
 			case stateShortDesc:
 				if m.shortDesc.Value() != "" {
+					m.shortDesc.Blur()
+					m.longDesc.Focus()
 					m.state = stateLongDesc
 				}
-			case stateLongDesc:
-				m.state = stateConfirm
+			case stateCategory:
+				m.saveEntry()
+				m.state = stateEntries
 			}
 		}
 	}
@@ -185,6 +443,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.shortDesc, cmd = m.shortDesc.Update(msg)
 	} else if m.state == stateLongDesc {
 		m.longDesc, cmd = m.longDesc.Update(msg)
+	} else if m.state == stateMigration {
+		m.migration, cmd = m.migration.Update(msg)
 	}
 
 	return m, cmd
@@ -202,12 +462,33 @@ func (m model) View() string {
 	switch m.state {
 	case stateCommitType:
 		s = "Select commit type (↑/↓ to move, enter to select):\n\n"
-		for i, t := range commitTypes {
+		if m.app.line != nil {
+			s = fmt.Sprintf("%s only takes patch releases (enter to select):\n\n", git.ReleaseBranch(*m.app.line))
+		}
+		for i, t := range m.types {
 			cursor := " "
 			if i == m.cursor {
 				cursor = ">"
 			}
-			s += fmt.Sprintf("%s %s\n", cursor, t)
+			if t == m.recommended {
+				s += fmt.Sprintf("%s %s (recommended)\n", cursor, t)
+			} else {
+				s += fmt.Sprintf("%s %s\n", cursor, t)
+			}
+		}
+		if len(m.drivers) > 0 {
+			if slices.Contains(m.types, m.recommended) {
+				s += fmt.Sprintf("\n%s is recommended because of:\n", m.recommended)
+			} else {
+				s += fmt.Sprintf("\nThese commits call for a %s release, which belongs on the main branch:\n", m.recommended)
+			}
+			for i, d := range m.drivers {
+				if i == maxDrivers {
+					s += fmt.Sprintf("  ... and %d more\n", len(m.drivers)-i)
+					break
+				}
+				s += fmt.Sprintf("  - %s\n", d)
+			}
 		}
 
 	case stateShortDesc:
@@ -215,23 +496,91 @@ func (m model) View() string {
 		s += m.shortDesc.View()
 
 	case stateLongDesc:
-		s = "Long description (optional):\n"
+		s = "Long description (optional):\n\n"
 		s += m.longDesc.View()
+		s += "\n\nctrl+s: continue"
+
+	case stateCategory:
+		s = "Select category (↑/↓ to move, enter to select):\n\n"
+		for i, c := range changelog.Categories {
+			cursor := " "
+			if i == m.categoryCursor {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, c)
+		}
+
+	case stateEntries:
+		s = m.viewEntries()
+
+	case stateMigration:
+		s = m.viewMigration()
 
 	case stateConfirm:
-		s = fmt.Sprintf("\nCommit Type: %s\nShort Description: %s\nLong Description: %s\n",
-			m.commitType, m.shortDesc.Value(), m.longDesc.Value())
+		s = fmt.Sprintf("\nCommit Type: %s\nEntries:\n", m.commitType)
+		for _, e := range m.entries {
+			s += fmt.Sprintf("  [%s] %s\n", e.Category, e.Short)
+			if e.Long != "" {
+				s += fmt.Sprintf("      %s\n", e.Long)
+			}
+		}
+		if m.commitType == version.Major {
+			if text := m.migrationText(); text != "" {
+				s += fmt.Sprintf("Migration:\n  %s\n", strings.ReplaceAll(text, "\n", "\n  "))
+			} else {
+				s += "Migration: none (skipped)\n"
+			}
+		}
 		s += "\nPress 'y' to confirm or 'n' to cancel"
 
 	case stateTagConfirm:
 		ver, _ := m.app.version.Read()
-		s = fmt.Sprintf("\nCreate git tag v%s? (y/n)", ver.String())
+		kind := "git tag"
+		switch {
+		case m.app.cfg.Tag.Sign:
+			kind = "signed git tag"
+		case m.app.cfg.Tag.Annotate:
+			kind = "annotated git tag"
+		}
+		s = fmt.Sprintf("\nCreate %s %s? (y/n)", kind, m.app.tags.Name(ver))
+
+	case statePushConfirm:
+		ver, _ := m.app.version.Read()
+		target := m.app.cfg.Push.Remote
+		if m.app.cfg.Push.Branch != "" {
+			target += "/" + m.app.cfg.Push.Branch
+		}
+		if m.tagged {
+			s = fmt.Sprintf("\nPush the release commit and tag %s to %s? (y/n)", m.app.tags.Name(ver), target)
+		} else {
+			s = fmt.Sprintf("\nPush the release commit to %s without a tag? (y/n)", target)
+		}
 	}
 
 	return s
 }
 
 func (m *model) saveChanges(createTag bool) error {
+	if err := m.app.preflight(m.ctx, m.commitType); err != nil {
+		return err
+	}
+	// Committing refuses other staged changes, which must stop the release
+	// before it writes any file.
+	if err := m.app.git.CheckStaged(m.ctx, m.releaseFiles()); err != nil {
+		return err
+	}
+
+	contributors, err := m.app.contributors(m.ctx)
+	if err != nil {
+		return fmt.Errorf("listing contributors: %w", err)
+	}
+
+	cur, err := m.app.version.Read()
+	if err != nil {
+		return fmt.Errorf("reading version: %w", err)
+	}
+	// A copy, as Bump may update the version read in place.
+	previous := *cur
 	if err := m.app.version.Bump(m.commitType); err != nil {
 		return fmt.Errorf("bumping version: %w", err)
 	}
@@ -241,13 +590,24 @@ func (m *model) saveChanges(createTag bool) error {
 		return fmt.Errorf("reading version: %w", err)
 	}
 
-	if err := m.app.log.Update(*ver, m.commitType, m.shortDesc.Value(), m.longDesc.Value()); err != nil {
+	if err := m.app.log.Update(*ver, m.commitType, m.entries, m.migrationText(), contributors); err != nil {
 		return fmt.Errorf("updating changelog: %w", err)
 	}
 
-	if err := m.app.git.Commit(m.ctx, m.shortDesc.Value()); err != nil {
+	message, err := m.app.commitMessage(ver, m.commitType, m.entries)
+	if err != nil {
+		return err
+	}
+	if err := m.app.git.Commit(m.ctx, message, m.releaseFiles()); err != nil {
 		return fmt.Errorf("committing changes: %w", err)
 	}
+	// The release stands without its metadata, so a failure to record it
+	// doesn't stop the tag.
+	if err := m.app.recordRelease(m.ctx, &previous, ver, m.commitType, m.entries); err != nil {
+		m.app.logger.Warn("release metadata not recorded", "error", err)
+	} else {
+		m.recorded = m.app.notes != nil
+	}
 
 	if createTag {
 		if err := m.createTag(); err != nil {
@@ -258,16 +618,93 @@ func (m *model) saveChanges(createTag bool) error {
 	return nil
 }
 
+// preflight checks that the repository is ready for a release of type t
+// before any file is modified.
+func (a *App) preflight(ctx context.Context, t version.Type) error {
+	if a.repo == nil {
+		return nil
+	}
+
+	next, err := a.version.Next(t)
+	if err != nil {
+		return fmt.Errorf("computing next version: %w", err)
+	}
+
+	files := []string{a.cfg.VersionFile, a.cfg.ChangelogFile}
+	if a.cfg.UpgradingFile != "" {
+		files = append(files, a.cfg.UpgradingFile)
+	}
+	files = append(files, a.cfg.ReleaseFiles...)
+	skip := make([]preflight.Check, 0, len(a.cfg.Preflight.Skip))
+	for _, c := range a.cfg.Preflight.Skip {
+		skip = append(skip, preflight.Check(c))
+	}
+
+	return preflight.Run(ctx, a.repo, preflight.Options{
+		ReleaseFiles:  files,
+		Branches:      a.cfg.Preflight.Branches,
+		Tag:           a.tags.Name(next),
+		IncludeStaged: a.cfg.IncludeStaged,
+		Skip:          skip,
+	})
+}
+
+// releaseFiles lists the files a release writes and the configured extra
+// release files, which are the only ones it commits.
+func (m *model) releaseFiles() []string {
+	files := []string{m.app.cfg.VersionFile, m.app.cfg.ChangelogFile}
+	if m.app.cfg.UpgradingFile != "" && m.migrationText() != "" {
+		files = append(files, m.app.cfg.UpgradingFile)
+	}
+	return append(files, m.app.cfg.ReleaseFiles...)
+}
+
 func (m *model) createTag() error {
 	ver, err := m.app.version.Read()
 	if err != nil {
 		return fmt.Errorf("reading version: %w", err)
 	}
 
-	if err := m.app.git.Tag(m.ctx, ver); err != nil {
+	var message string
+	if m.app.cfg.Tag.Annotate || m.app.cfg.Tag.Sign {
+		notes, err := changelog.NotesFile(m.app.cfg.ChangelogFile, ver.String())
+		if err != nil {
+			return fmt.Errorf("reading release notes: %w", err)
+		}
+		message = strings.TrimSpace(fmt.Sprintf("Release %s\n\n%s", ver, notes))
+	}
+
+	if err := m.app.git.Tag(m.ctx, ver, message); err != nil {
 		return fmt.Errorf("creating tag: %w", err)
 	}
+	m.tagged = true
 
 	return nil
 }
 
+// push sends the release commit, and its tag when one was created, to the
+// configured remote in a single atomic push. The release metadata follows
+// in a push of its own, as notes others added on the remote must not hold
+// up the release.
+func (m *model) push() error {
+	var tag string
+	if m.tagged {
+		ver, err := m.app.version.Read()
+		if err != nil {
+			return fmt.Errorf("reading version: %w", err)
+		}
+		tag = m.app.tags.Name(ver)
+	}
+
+	if err := m.app.git.Push(m.ctx, m.app.cfg.Push.Remote, m.app.cfg.Push.Branch, tag); err != nil {
+		return fmt.Errorf("pushing release: %w", err)
+	}
+
+	if m.recorded {
+		if err := m.app.notes.PushRef(m.ctx, m.app.cfg.Push.Remote, notes.Ref); err != nil {
+			m.app.logger.Warn("release metadata not pushed", "error", err)
+		}
+	}
+
+	return nil
+}
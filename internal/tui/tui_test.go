@@ -4,10 +4,17 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/WagnerMatos/semver/internal/changelog"
 	"github.com/WagnerMatos/semver/internal/config"
+	"github.com/WagnerMatos/semver/internal/git"
 	"github.com/WagnerMatos/semver/internal/version"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -42,31 +49,89 @@ func (m *mockVersionService) Write(v *version.Version) error {
 	return nil
 }
 
+func (m *mockVersionService) Next(t version.Type) (*version.Version, error) {
+	next := *m.version
+	if err := next.Bump(t); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
 func (m *mockVersionService) Bump(t version.Type) error {
 	if m.bumpErr != nil {
 		return m.bumpErr
 	}
-	return nil
+	return m.version.Bump(t)
 }
 
 type mockGitService struct {
 	commitErr error
+	stagedErr error
 	tagErr    error
+	remoteErr error
+	latestTag string
+	commits   []git.Commit
+	logErr    error
+	author    string
+	// tagged and tagMessage record whether a tag was created and the
+	// message of the last one.
+	tagged     bool
+	tagMessage string
+	pushErr    error
+	// committed records the files of the last commit.
+	committed []string
+	// pushed records the remote, branch and tag of each push.
+	pushed []string
 }
 
-func (m *mockGitService) Commit(ctx context.Context, message string) error {
+func (m *mockGitService) Commit(ctx context.Context, message string, files []string) error {
+	m.committed = files
 	return m.commitErr
 }
 
-func (m *mockGitService) Tag(ctx context.Context, ver *version.Version) error {
+func (m *mockGitService) CheckStaged(ctx context.Context, files []string) error {
+	return m.stagedErr
+}
+
+func (m *mockGitService) Tag(ctx context.Context, ver *version.Version, message string) error {
+	m.tagged, m.tagMessage = true, message
 	return m.tagErr
 }
 
+func (m *mockGitService) VerifyTag(ctx context.Context, tag string) (string, error) {
+	return "", m.tagErr
+}
+
+func (m *mockGitService) Push(ctx context.Context, remote, branch, tag string) error {
+	m.pushed = append(m.pushed, remote, branch, tag)
+	return m.pushErr
+}
+
+func (m *mockGitService) LatestTag(ctx context.Context) (string, error) {
+	return m.latestTag, m.logErr
+}
+
+func (m *mockGitService) Log(ctx context.Context, since string) ([]git.Commit, error) {
+	return m.commits, m.logErr
+}
+
+func (m *mockGitService) Author(ctx context.Context) (string, error) {
+	return m.author, nil
+}
+
+func (m *mockGitService) RemoteURL(ctx context.Context, name string) (string, error) {
+	return "", m.remoteErr
+}
+
 type mockChangelogService struct {
-	updateErr error
+	updateErr    error
+	migration    string
+	contributors []changelog.Contributor
 }
 
-func (m *mockChangelogService) Update(v version.Version, t version.Type, shortDesc, longDesc string) error {
+func (m *mockChangelogService) Update(v version.Version, t version.Type, entries []changelog.Entry, migration string, contributors []changelog.Contributor) error {
+	m.migration = migration
+	m.contributors = contributors
 	return m.updateErr
 }
 
@@ -115,15 +180,23 @@ func TestModel_Update(t *testing.T) {
 			name:       "confirm tag",
 			msg:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")},
 			initState:  stateTagConfirm,
-			wantState:  stateTagConfirm,
+			wantState:  statePushConfirm,
 			cursor:     0,
 			wantCursor: 0,
 		},
 		{
-			name:       "cancel tag",
+			name:       "decline tag",
 			msg:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")},
 			initState:  stateTagConfirm,
-			wantState:  stateTagConfirm,
+			wantState:  statePushConfirm,
+			cursor:     0,
+			wantCursor: 0,
+		},
+		{
+			name:       "confirm push",
+			msg:        tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")},
+			initState:  statePushConfirm,
+			wantState:  statePushConfirm,
 			cursor:     0,
 			wantCursor: 0,
 		},
@@ -134,7 +207,7 @@ func TestModel_Update(t *testing.T) {
 			app := &App{
 				cfg:     &config.Config{},
 				logger:  slog.Default(),
-				version: &mockVersionService{version: &version.Version{1, 0, 0}},
+				version: &mockVersionService{version: &version.Version{Major: 1, Minor: 0, Patch: 0}},
 				git:     &mockGitService{},
 				log:     &mockChangelogService{},
 			}
@@ -163,6 +236,7 @@ func TestSaveChanges(t *testing.T) {
 		bumpErr   error
 		readErr   error
 		updateErr error
+		stagedErr error
 		commitErr error
 		tagErr    error
 		createTag bool
@@ -193,6 +267,11 @@ func TestSaveChanges(t *testing.T) {
 			updateErr: errTest,
 			wantErr:   true,
 		},
+		{
+			name:      "other changes staged",
+			stagedErr: git.ErrStagedChanges,
+			wantErr:   true,
+		},
 		{
 			name:      "commit error",
 			commitErr: errTest,
@@ -208,15 +287,17 @@ func TestSaveChanges(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			versionSvc := &mockVersionService{
+				version: &version.Version{Major: 1, Minor: 0, Patch: 0},
+				bumpErr: tt.bumpErr,
+				readErr: tt.readErr,
+			}
 			app := &App{
-				cfg:    &config.Config{},
-				logger: slog.Default(),
-				version: &mockVersionService{
-					version: &version.Version{1, 0, 0},
-					bumpErr: tt.bumpErr,
-					readErr: tt.readErr,
-				},
+				cfg:     &config.Config{},
+				logger:  slog.Default(),
+				version: versionSvc,
 				git: &mockGitService{
+					stagedErr: tt.stagedErr,
 					commitErr: tt.commitErr,
 					tagErr:    tt.tagErr,
 				},
@@ -230,28 +311,103 @@ func TestSaveChanges(t *testing.T) {
 				app:        app,
 				commitType: version.Major,
 				shortDesc:  textinput.New(),
-				longDesc:   textinput.New(),
+				longDesc:   textarea.New(),
 			}
 
 			err := m.saveChanges(tt.createTag)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("saveChanges() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.stagedErr != nil && versionSvc.version.String() != "1.0.0" {
+				t.Errorf("saveChanges() bumped the version to %s before refusing staged changes", versionSvc.version)
+			}
+		})
+	}
+}
+
+func TestReleaseFiles(t *testing.T) {
+	cfg := &config.Config{VersionFile: "VERSION.md", ChangelogFile: "CHANGELOG.md"}
+	withGuide := &config.Config{VersionFile: "VERSION.md", ChangelogFile: "CHANGELOG.md", UpgradingFile: "UPGRADING.md"}
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		commitType version.Type
+		migration  string
+		want       []string
+	}{
+		{
+			name:       "version and changelog",
+			cfg:        cfg,
+			commitType: version.Minor,
+			want:       []string{"VERSION.md", "CHANGELOG.md"},
+		},
+		{
+			name:       "upgrade guide untouched without notes",
+			cfg:        withGuide,
+			commitType: version.Major,
+			want:       []string{"VERSION.md", "CHANGELOG.md"},
+		},
+		{
+			name:       "upgrade guide with migration notes",
+			cfg:        withGuide,
+			commitType: version.Major,
+			migration:  "Rename the config file.",
+			want:       []string{"VERSION.md", "CHANGELOG.md", "UPGRADING.md"},
+		},
+		{
+			name:       "configured release files",
+			cfg:        &config.Config{VersionFile: "VERSION.md", ChangelogFile: "CHANGELOG.md", ReleaseFiles: []string{"package.json"}},
+			commitType: version.Minor,
+			want:       []string{"VERSION.md", "CHANGELOG.md", "package.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &model{
+				app:            &App{cfg: tt.cfg},
+				commitType:     tt.commitType,
+				migrationNotes: newMigrationNotes(),
+			}
+			m.migration.SetValue(tt.migration)
+
+			if got := m.releaseFiles(); !slices.Equal(got, tt.want) {
+				t.Errorf("releaseFiles() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
 
 func TestCreateTag(t *testing.T) {
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	content := "# Changelog\n\n## [1.0.0] - 2024-12-24\n### Added\n- Feature\n\n## [0.9.0] - 2024-12-01\n- Old\n"
+	if err := os.WriteFile(changelogFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write changelog: %v", err)
+	}
+
 	tests := []struct {
-		name    string
-		readErr error
-		tagErr  error
-		wantErr bool
+		name        string
+		tag         config.TagConfig
+		readErr     error
+		tagErr      error
+		wantMessage string
+		wantErr     bool
 	}{
 		{
 			name:    "successful tag creation",
 			wantErr: false,
 		},
+		{
+			name:        "annotated with the release notes",
+			tag:         config.TagConfig{Annotate: true},
+			wantMessage: "Release 1.0.0\n\n### Added\n- Feature",
+		},
+		{
+			name:        "signed tags are annotated",
+			tag:         config.TagConfig{Sign: true},
+			wantMessage: "Release 1.0.0\n\n### Added\n- Feature",
+		},
 		{
 			name:    "read error",
 			readErr: errTest,
@@ -266,16 +422,15 @@ func TestCreateTag(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			gitSvc := &mockGitService{tagErr: tt.tagErr}
 			app := &App{
-				cfg:    &config.Config{},
+				cfg:    &config.Config{ChangelogFile: changelogFile, Tag: tt.tag},
 				logger: slog.Default(),
 				version: &mockVersionService{
-					version: &version.Version{1, 0, 0},
+					version: &version.Version{Major: 1, Minor: 0, Patch: 0},
 					readErr: tt.readErr,
 				},
-				git: &mockGitService{
-					tagErr: tt.tagErr,
-				},
+				git: gitSvc,
 			}
 
 			m := &model{
@@ -287,9 +442,94 @@ func TestCreateTag(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createTag() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if gitSvc.tagMessage != tt.wantMessage {
+				t.Errorf("tag message = %q, want %q", gitSvc.tagMessage, tt.wantMessage)
+			}
 		})
 	}
 }
 
-var errTest = errors.New("test error")
+func TestPush(t *testing.T) {
+	tests := []struct {
+		name       string
+		push       config.PushConfig
+		tagged     bool
+		pushErr    error
+		wantPushed []string
+		wantErr    bool
+	}{
+		{
+			name:       "commit and tag",
+			push:       config.PushConfig{Remote: "origin"},
+			tagged:     true,
+			wantPushed: []string{"origin", "", "v1.0.0"},
+		},
+		{
+			name:       "configured branch without a tag",
+			push:       config.PushConfig{Remote: "upstream", Branch: "main"},
+			wantPushed: []string{"upstream", "main", ""},
+		},
+		{
+			name:       "push error",
+			push:       config.PushConfig{Remote: "origin"},
+			tagged:     true,
+			pushErr:    errTest,
+			wantPushed: []string{"origin", "", "v1.0.0"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitSvc := &mockGitService{pushErr: tt.pushErr}
+			m := &model{
+				ctx: context.Background(),
+				app: &App{
+					cfg:     &config.Config{Push: tt.push},
+					logger:  slog.Default(),
+					version: &mockVersionService{version: &version.Version{Major: 1}},
+					git:     gitSvc,
+				},
+				tagged: tt.tagged,
+			}
+
+			err := m.push()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("push() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(gitSvc.pushed, tt.wantPushed) {
+				t.Errorf("pushed %q, want %q", gitSvc.pushed, tt.wantPushed)
+			}
+		})
+	}
+}
 
+func TestPush_WithoutTag(t *testing.T) {
+	gitSvc := &mockGitService{}
+	app := &App{
+		cfg:     &config.Config{Push: config.PushConfig{Remote: "origin"}},
+		logger:  slog.Default(),
+		version: &mockVersionService{version: &version.Version{Major: 1}},
+		git:     gitSvc,
+	}
+	m := initialModel(context.Background(), app)
+	m.state = stateTagConfirm
+
+	m = send(m, "n")
+	if m.state != statePushConfirm || m.quitting {
+		t.Fatalf("declining the tag: state = %v, quitting %v, want the push offered", m.state, m.quitting)
+	}
+	if view := m.View(); !strings.Contains(view, "without a tag") {
+		t.Errorf("View() = %q, want the push offered without a tag", view)
+	}
+
+	m = send(m, "y")
+	if gitSvc.tagged {
+		t.Error("declining the tag created one")
+	}
+	if want := []string{"origin", "", ""}; !slices.Equal(gitSvc.pushed, want) {
+		t.Errorf("pushed %q, want %q", gitSvc.pushed, want)
+	}
+}
+
+var errTest = errors.New("test error")
@@ -12,6 +12,10 @@ import (
 var (
 	ErrInvalidVersion = errors.New("invalid version format")
 	ErrInvalidType    = errors.New("invalid version type")
+	ErrInvalidLine    = errors.New("invalid maintenance line")
+	// ErrMaintenanceLine reports a minor or major release asked of a
+	// maintenance line, which only takes patch releases.
+	ErrMaintenanceLine = errors.New("only patch releases are allowed on a maintenance line")
 )
 
 type Type string
@@ -32,19 +36,68 @@ type Service interface {
 	Read() (*Version, error)
 	Write(*Version) error
 	Bump(Type) error
+	Next(Type) (*Version, error)
 	GetLatestVersion() (*Version, error)
 }
 
+// Line is a maintenance line: the patch releases of one minor version, such
+// as 1.4.
+type Line struct {
+	Major int
+	Minor int
+}
+
+func (l Line) String() string {
+	return fmt.Sprintf("%d.%d", l.Major, l.Minor)
+}
+
+// Contains reports whether v is a release of the line.
+func (l Line) Contains(v *Version) bool {
+	return v.Major == l.Major && v.Minor == l.Minor
+}
+
+// ParseLine parses a line written as "1.4".
+func ParseLine(s string) (Line, error) {
+	var l Line
+	if _, err := fmt.Sscanf(s, "%d.%d", &l.Major, &l.Minor); err != nil {
+		return Line{}, fmt.Errorf("%w: %v", ErrInvalidLine, err)
+	}
+	if l.String() != s {
+		return Line{}, fmt.Errorf("%w: %q, want MAJOR.MINOR", ErrInvalidLine, s)
+	}
+	return l, nil
+}
+
 type FileService struct {
 	filepath string
 	version  *Version
+	line     *Line
+	// lineLatest is the highest release of line, nil when unknown.
+	lineLatest *Version
+}
+
+type Option func(*FileService)
+
+// WithLine restricts the service to patch releases of line, as on a
+// maintenance branch. latest is the highest release of the line, typically
+// read from its tags; versions are numbered after it or after the version
+// file, whichever is higher, rather than after the latest release overall.
+func WithLine(line Line, latest *Version) Option {
+	return func(s *FileService) {
+		s.line = &line
+		s.lineLatest = latest
+	}
 }
 
-func NewFileService(filepath string) *FileService {
-	return &FileService{
+func NewFileService(filepath string, opts ...Option) *FileService {
+	s := &FileService{
 		filepath: filepath,
 		version:  &Version{0, 1, 0}, // Default version
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (v *Version) String() string {
@@ -121,7 +174,34 @@ func (s *FileService) Read() (*Version, error) {
 	return ver, nil
 }
 
+// GetLatestVersion returns the current release. When it has to be read from
+// the changelog, releases marked [YANKED] are skipped. On a maintenance line
+// it is the highest release of the line.
 func (s *FileService) GetLatestVersion() (*Version, error) {
+	if s.line != nil {
+		return s.lineVersion(true)
+	}
+	return s.latestVersion(true)
+}
+
+// lineVersion returns the highest release of the maintenance line, from its
+// tags or the version file.
+func (s *FileService) lineVersion(skipYanked bool) (*Version, error) {
+	ver, err := s.latestVersion(skipYanked)
+	if err != nil {
+		return nil, err
+	}
+	latest := s.lineLatest
+	if s.line.Contains(ver) && (latest == nil || ver.Compare(latest) > 0) {
+		latest = ver
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %s has no release to patch", ErrMaintenanceLine, s.line)
+	}
+	return &Version{latest.Major, latest.Minor, latest.Patch}, nil
+}
+
+func (s *FileService) latestVersion(skipYanked bool) (*Version, error) {
 	// Try reading from VERSION.md first
 	data, err := os.ReadFile(s.filepath)
 	if err != nil {
@@ -148,6 +228,9 @@ func (s *FileService) GetLatestVersion() (*Version, error) {
 		lines := strings.Split(string(data), "\n")
 		for _, line := range lines {
 			line = strings.TrimSpace(line)
+			if skipYanked && strings.Contains(line, "[YANKED]") {
+				continue
+			}
 			if strings.HasPrefix(line, "## [") && strings.Contains(line, "]") {
 				verStr := strings.TrimPrefix(line, "## [")
 				verStr = strings.Split(verStr, "]")[0]
@@ -178,30 +261,51 @@ func (s *FileService) Write(v *Version) error {
 	return nil
 }
 
-func (s *FileService) Bump(t Type) error {
+// Next returns the version Bump would write, without writing it.
+func (s *FileService) Next(t Type) (*Version, error) {
+	if s.line != nil {
+		if t != Patch {
+			return nil, fmt.Errorf("%w: cannot make a %s release of %s", ErrMaintenanceLine, t, s.line)
+		}
+		ver, err := s.lineVersion(false)
+		if err != nil {
+			return nil, err
+		}
+		ver.Patch++
+		return ver, nil
+	}
+
 	initialVersion := &Version{0, 1, 0}
 
 	// If file doesn't exist or is invalid, handle special cases
 	if _, err := os.Stat(s.filepath); os.IsNotExist(err) {
 		if t == Major {
-			return s.Write(&Version{1, 0, 0})
+			return &Version{1, 0, 0}, nil
 		} else if t == Patch {
-			return s.Write(&Version{0, 1, 1})
+			return &Version{0, 1, 1}, nil
 		}
-		return s.Write(initialVersion)
+		return initialVersion, nil
 	}
 
-	// Try to read existing version
-	ver, err := s.GetLatestVersion()
+	// Try to read existing version. Yanked versions still count here, as a
+	// yanked version number must never be released again.
+	ver, err := s.latestVersion(false)
 	if err != nil || ver.Compare(initialVersion) == 0 {
 		// If invalid version, reset to initial version
-		return s.Write(initialVersion)
+		return initialVersion, nil
 	}
 
 	// Otherwise, bump the existing version
 	if err := ver.Bump(t); err != nil {
-		return fmt.Errorf("bumping version: %w", err)
+		return nil, fmt.Errorf("bumping version: %w", err)
 	}
+	return ver, nil
+}
 
+func (s *FileService) Bump(t Type) error {
+	ver, err := s.Next(t)
+	if err != nil {
+		return err
+	}
 	return s.Write(ver)
 }
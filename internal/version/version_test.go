@@ -1,6 +1,7 @@
 package version
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -168,6 +169,27 @@ func TestFileService_GetLatestVersion(t *testing.T) {
 			want:    &Version{2, 0, 0},
 			wantErr: false,
 		},
+		{
+			name: "yanked releases in CHANGELOG.md are skipped",
+			setupFiles: func(t *testing.T, dir string) {
+				err := os.WriteFile(versionFile, []byte("invalid"), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				changelogContent := `## [2.0.0] - 2024-12-23 [YANKED] Breaks the config loader
+### Major
+- desc
+## [1.0.0] - 2024-12-23
+### Major
+- Initial commit`
+				err = os.WriteFile(changelogFile, []byte(changelogContent), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:    &Version{1, 0, 0},
+			wantErr: false,
+		},
 		{
 			name: "only invalid VERSION.md exists",
 			setupFiles: func(t *testing.T, dir string) {
@@ -270,6 +292,23 @@ func TestFileService_Bump(t *testing.T) {
 			want:     "0.1.0", // Resets to a valid version
 			wantErr:  false,
 		},
+		{
+			name: "never reuse a yanked version",
+			setupFiles: func(t *testing.T, dir string) {
+				err := os.WriteFile(versionFile, []byte("invalid"), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				changelogContent := "## [1.1.0] - 2024-12-24 [YANKED]\n## [1.0.0] - 2024-12-23\n"
+				err = os.WriteFile(changelogFile, []byte(changelogContent), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			bumpType: Minor,
+			want:     "1.2.0",
+			wantErr:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -280,6 +319,10 @@ func TestFileService_Bump(t *testing.T) {
 			tt.setupFiles(t, dir)
 
 			fs := NewFileService(versionFile)
+			if next, err := fs.Next(tt.bumpType); err != nil || next.String() != tt.want {
+				t.Errorf("Next() = %v, %v; want %s", next, err, tt.want)
+			}
+
 			err := fs.Bump(tt.bumpType)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Bump() error = %v, wantErr %v", err, tt.wantErr)
@@ -298,3 +341,115 @@ func TestFileService_Bump(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Line
+		wantErr bool
+	}{
+		{in: "1.4", want: Line{1, 4}},
+		{in: "0.12", want: Line{0, 12}},
+		{in: "1.4.2", wantErr: true},
+		{in: "v1.4", wantErr: true},
+		{in: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLine(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidLine) {
+				t.Errorf("ParseLine() error = %v, want ErrInvalidLine", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileService_Line(t *testing.T) {
+	dir := t.TempDir()
+	versionFile := filepath.Join(dir, "VERSION.md")
+
+	tests := []struct {
+		name       string
+		file       string
+		latest     *Version
+		bumpType   Type
+		wantLatest string
+		wantNext   string
+		wantErr    error
+	}{
+		{
+			name:       "numbered after the highest tag of the line",
+			file:       "2.1.0",
+			latest:     &Version{1, 4, 2},
+			bumpType:   Patch,
+			wantLatest: "1.4.2",
+			wantNext:   "1.4.3",
+		},
+		{
+			name:       "version file ahead of the tags",
+			file:       "1.4.3",
+			latest:     &Version{1, 4, 2},
+			bumpType:   Patch,
+			wantLatest: "1.4.3",
+			wantNext:   "1.4.4",
+		},
+		{
+			name:       "no tags yet",
+			file:       "1.4.0",
+			bumpType:   Patch,
+			wantLatest: "1.4.0",
+			wantNext:   "1.4.1",
+		},
+		{
+			name:       "minor refused",
+			file:       "1.4.2",
+			latest:     &Version{1, 4, 2},
+			bumpType:   Minor,
+			wantLatest: "1.4.2",
+			wantErr:    ErrMaintenanceLine,
+		},
+		{
+			name:       "major refused",
+			file:       "1.4.2",
+			latest:     &Version{1, 4, 2},
+			bumpType:   Major,
+			wantLatest: "1.4.2",
+			wantErr:    ErrMaintenanceLine,
+		},
+		{
+			name:     "nothing to patch",
+			file:     "2.1.0",
+			bumpType: Patch,
+			wantErr:  ErrMaintenanceLine,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(versionFile, []byte(tt.file), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fs := NewFileService(versionFile, WithLine(Line{1, 4}, tt.latest))
+			latest, err := fs.GetLatestVersion()
+			if tt.wantLatest != "" && (err != nil || latest.String() != tt.wantLatest) {
+				t.Errorf("GetLatestVersion() = %v, %v; want %s", latest, err, tt.wantLatest)
+			}
+
+			next, err := fs.Next(tt.bumpType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && next.String() != tt.wantNext {
+				t.Errorf("Next() = %v, want %s", next, tt.wantNext)
+			}
+		})
+	}
+}